@@ -0,0 +1,96 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 16:48:02
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 16:48:02
+ * @Description: 访问日志写入log目录下按时间切分的文件
+ */
+package bootstrap
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/liziwei01/simple-boot/library/extension/writer"
+	"github.com/liziwei01/simple-boot/library/lifecycle"
+)
+
+const (
+	accessLogSuffix       = ".access.log"
+	defaultAccessLogRule  = "1hour"
+	defaultAccessLogQueue = 4096
+	// 文件写入跟不上时最多等待的时间, 超过后丢弃日志, 避免阻塞请求
+	accessLogWriteTimeout = 100 * time.Millisecond
+	// 定期落盘及检查文件是否被删除的间隔
+	accessLogFlushInterval = time.Second
+	// FileName 为该值时输出到 DefaultWriter
+	accessLogStdout = "stdout"
+)
+
+// AccessLogConfig 访问日志配置
+//
+//	[HTTPServer.Middlewares.AccessLog]
+//	Format = "json"
+//	Rule = "1day"
+//	MaxFileNum = 7
+type AccessLogConfig struct {
+	// Format 日志格式, text 或 json, 默认text
+	Format string
+	// FileName 日志文件, 默认为 <APPName>.access.log, 相对路径相对于log目录
+	// 为 stdout 时输出到 DefaultWriter
+	FileName string
+	// Rule 文件切分规则, 如 1hour、1day、no, 见 writer.RegisterRotateRule, 默认1hour
+	Rule string
+	// MaxFileNum 保留的日志文件数, 默认为0, 不清理
+	MaxFileNum int
+	// BufferSize 异步写入的队列长度, 默认4096
+	BufferSize int
+}
+
+// newAccessLogWriter 创建访问日志的writer, 应用退出时关闭
+func newAccessLogWriter(app *AppServer) (io.Writer, error) {
+	alc := app.Config.HTTPServer.Middlewares.AccessLog
+	if alc.FileName == accessLogStdout {
+		return DefaultWriter, nil
+	}
+	fileName := alc.FileName
+	if fileName == "" {
+		fileName = app.Config.APPName + accessLogSuffix
+	}
+	if !filepath.IsAbs(fileName) {
+		fileName = filepath.Join(app.Config.Env.LogDir(), fileName)
+	}
+	rule := alc.Rule
+	if rule == "" {
+		rule = defaultAccessLogRule
+	}
+	producer, err := writer.NewSimpleRotateProducer(rule, fileName)
+	if err != nil {
+		return nil, err
+	}
+	rotate, err := writer.NewRotate(&writer.RotateOption{
+		FileProducer:  producer,
+		FlushDuration: accessLogFlushInterval,
+		CheckDuration: accessLogFlushInterval,
+		MaxFileNum:    alc.MaxFileNum,
+	})
+	if err != nil {
+		_ = producer.Stop()
+		return nil, err
+	}
+	queue := alc.BufferSize
+	if queue <= 0 {
+		queue = defaultAccessLogQueue
+	}
+	w := writer.NewAsync(queue, accessLogWriteTimeout, rotate)
+	// 在http服务关闭后执行, 剩余的日志会被写入文件; Setup 失败时直接关闭
+	app.setupHooks = append(app.setupHooks, lifecycle.Hook{
+		Name: "access_log",
+		OnStop: func(ctx context.Context) error {
+			return w.Close()
+		},
+	})
+	return w, nil
+}
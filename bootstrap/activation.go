@@ -0,0 +1,135 @@
+//go:build !windows
+
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 18:20:05
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 18:20:05
+ * @Description: socket activation, 使用systemd等按 LISTEN_FDS 协议传递过来的socket
+ */
+package bootstrap
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	envListenPID     = "LISTEN_PID"
+	envListenFDs     = "LISTEN_FDS"
+	envListenFDNames = "LISTEN_FDNAMES"
+)
+
+// activated 一个传递过来的socket
+type activated struct {
+	name     string
+	listener net.Listener
+	used     bool
+}
+
+var (
+	activatedOnce      sync.Once
+	activatedListeners []*activated
+	activatedErr       error
+)
+
+// activatedListener 按名字(systemd的FileDescriptorName)或地址匹配传递过来的socket, 没有则返回nil
+func activatedListener(name string, addr string) (net.Listener, error) {
+	activatedOnce.Do(func() {
+		activatedListeners, activatedErr = loadActivatedListeners()
+	})
+	if activatedErr != nil {
+		return nil, activatedErr
+	}
+	for _, a := range activatedListeners {
+		if !a.used && a.name == name {
+			a.used = true
+			return a.listener, nil
+		}
+	}
+	for _, a := range activatedListeners {
+		if !a.used && sameAddress(a.listener.Addr(), addr) {
+			a.used = true
+			return a.listener, nil
+		}
+	}
+	return nil, nil
+}
+
+// closeUnusedActivated 关闭没有匹配到任何监听的socket, 在所有监听创建后调用
+func closeUnusedActivated() {
+	activatedOnce.Do(func() {
+		activatedListeners, activatedErr = loadActivatedListeners()
+	})
+	for _, a := range activatedListeners {
+		if a.used {
+			continue
+		}
+		a.used = true
+		log.Printf("[activation] socket %q %s not used by any listener, closed\n", a.name, a.listener.Addr())
+		_ = a.listener.Close()
+	}
+}
+
+// loadActivatedListeners 读取 LISTEN_PID、LISTEN_FDS、LISTEN_FDNAMES, 读取后清除, 不再传给子进程
+func loadActivatedListeners() ([]*activated, error) {
+	pid, fds := os.Getenv(envListenPID), os.Getenv(envListenFDs)
+	names := strings.Split(os.Getenv(envListenFDNames), ":")
+	_ = os.Unsetenv(envListenPID)
+	_ = os.Unsetenv(envListenFDs)
+	_ = os.Unsetenv(envListenFDNames)
+	if pid != strconv.Itoa(os.Getpid()) || fds == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(fds)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s=%q", envListenFDs, fds)
+	}
+	listeners := make([]*activated, 0, n)
+	for i := 0; i < n; i++ {
+		fd := inheritFDStart + i
+		f := os.NewFile(uintptr(fd), "activated:"+strconv.Itoa(fd))
+		ln, err := net.FileListener(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("socket activation fd %d: %w", fd, err)
+		}
+		a := &activated{listener: ln}
+		if i < len(names) {
+			a.name = names[i]
+		}
+		listeners = append(listeners, a)
+	}
+	return listeners, nil
+}
+
+// sameAddress 监听的地址是否与配置的一致
+// tcp 端口相同, 且配置的host为空或IP相同; unix 路径相同
+func sameAddress(lnAddr net.Addr, addr string) bool {
+	network, address := parseListen(addr)
+	if network == "unix" {
+		return lnAddr.Network() == "unix" && lnAddr.String() == address
+	}
+	tcpAddr, ok := lnAddr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	portNum, err := net.LookupPort("tcp", port)
+	if err != nil || portNum != tcpAddr.Port {
+		return false
+	}
+	if host == "" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.Equal(tcpAddr.IP)
+}
@@ -0,0 +1,55 @@
+//go:build !windows
+
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-19 11:48:22
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-19 11:48:22
+ * @Description: socket activation 测试
+ */
+package bootstrap
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestCloseUnusedActivated(t *testing.T) {
+	used, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer used.Close()
+	unused, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unused.Close()
+	// 不读取环境变量, 直接使用测试的listener
+	activatedOnce.Do(func() {})
+	activatedListeners = []*activated{{name: "main", listener: used}, {name: "other", listener: unused}}
+	t.Cleanup(func() { activatedListeners = nil })
+
+	s := &server{ListenerConfig: ListenerConfig{Name: "main", Listen: used.Addr().String()}, tcpKeepAlive: time.Second}
+	if err := s.listen(); err != nil {
+		t.Fatal(err)
+	}
+	if s.rawListener != used {
+		t.Fatalf("activated listener not used: %v", s.rawListener.Addr())
+	}
+	if _, ok := s.listener.(*keepAliveListener); !ok {
+		t.Fatalf("keep-alive not applied: %T", s.listener)
+	}
+
+	closeUnusedActivated()
+	if conn, err := net.Dial("tcp", unused.Addr().String()); err == nil {
+		conn.Close()
+		t.Fatal("unused socket not closed")
+	}
+	conn, err := net.Dial("tcp", used.Addr().String())
+	if err != nil {
+		t.Fatalf("used socket closed: %v", err)
+	}
+	conn.Close()
+}
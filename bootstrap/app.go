@@ -10,8 +10,11 @@ package bootstrap
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
+	"log"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -19,6 +22,7 @@ import (
 
 	"github.com/liziwei01/simple-boot/library/conf"
 	"github.com/liziwei01/simple-boot/library/env"
+	"github.com/liziwei01/simple-boot/library/metrics"
 
 	"github.com/gin-gonic/gin"
 )
@@ -47,17 +51,78 @@ type Config struct {
 		ReadTimeout  int // ms
 		WriteTimeout int // ms
 		IdleTimeout  int // ms
+		// 读取请求header的超时时间, 防止slowloris, 默认同ReadTimeout
+		ReadHeaderTimeout int // ms
+		// 请求header的最大字节数, 默认1MB
+		MaxHeaderBytes int
+		// 每个监听的最大并发连接数, 超过后新连接排队等待, 默认0不限制
+		MaxConns int
+		// 关闭http的keep-alive, 每个请求处理完后关闭连接
+		DisableKeepAlives bool
+		// tcp keep-alive的探测间隔, 默认15s, 小于0关闭
+		TCPKeepAlive int // ms
+		// 收到退出信号后等待请求处理完成的最长时间, 默认10s
+		ShutdownTimeout int // ms
+
+		// 主监听的 HTTP/2 cleartext 及 unix socket 权限, 见 ListenerConfig
+		H2C         bool
+		SocketMode  string
+		SocketGroup string
+
+		// 除 Listen 外的其他监听, 与主监听一起启动、一起退出
+		Listeners []ListenerConfig
+
+		// https的证书配置
+		TLS TLSConfig
+
+		// 业务路由使用的中间件
+		Middlewares MiddlewaresConfig
+
+		// pprof等调试接口
+		Debug DebugConfig
+
+		// 由路由表生成的 OpenAPI 文档
+		OpenAPI OpenAPIConfig
+	}
+	// 各模块的配置, [Modules.<Name>], 见 Module
+	Modules map[string]interface{}
+}
+
+// hasAdminListener 是否配置了admin监听
+func (c *Config) hasAdminListener() bool {
+	for _, lc := range c.HTTPServer.Listeners {
+		if lc.Admin {
+			return true
+		}
 	}
+	return false
 }
 
 // ParserAppConfig
 func ParserAppConfig(filePath string) (*Config, error) {
+	return parseAppConfig(filePath, nil)
+}
+
+// parseAppConfig 解析配置后使用sets覆盖, 如 HTTPServer.Listen=:9000
+// 依次叠加 app.<RunMode>.toml、app.local.toml, RunMode 取自 app.toml 或 sets, 叠加配置中的 RunMode 不影响叠加哪个文件
+func parseAppConfig(filePath string, sets []string) (*Config, error) {
 	confPath, err := filepath.Abs(filePath)
 	if err != nil {
 		return nil, err
 	}
+	runMode, err := parseRunMode(confPath, sets)
+	if err != nil {
+		return nil, err
+	}
+	parser := conf.Default.CloneWithEnv(runModeEnv{AppEnv: env.Default, runMode: runMode})
+	if sources := parser.Sources(confPath); len(sources) > 1 {
+		log.Printf("[conf] %s merged from %q\n", filepath.Base(confPath), sources)
+	}
 	var c *Config
-	if err := conf.Parse(confPath, &c); err != nil {
+	if err := parser.Parse(confPath, &c); err != nil {
+		return nil, err
+	}
+	if err := applyOverrides(c, sets); err != nil {
 		return nil, err
 	}
 	// parse and set global conf
@@ -74,51 +139,186 @@ func ParserAppConfig(filePath string) (*Config, error) {
 	return c, nil
 }
 
+// parseRunMode 只解析confPath本身, 得到使用sets覆盖后的RunMode
+func parseRunMode(confPath string, sets []string) (string, error) {
+	content, err := os.ReadFile(confPath)
+	if err != nil {
+		return "", err
+	}
+	var c Config
+	if err := conf.ParseBytes(filepath.Ext(confPath), content, &c); err != nil {
+		return "", err
+	}
+	if err := applyOverrides(&c, sets); err != nil {
+		return "", err
+	}
+	if c.RunMode == "" {
+		return env.DefaultRunMode, nil
+	}
+	return c.RunMode, nil
+}
+
+// runModeEnv 替换了RunMode的环境信息, 用于在创建应用的环境前按RunMode查找叠加配置
+type runModeEnv struct {
+	env.AppEnv
+	runMode string
+}
+
+// RunMode 覆盖 env.AppEnv 的 RunMode
+func (e runModeEnv) RunMode() string {
+	return e.runMode
+}
+
 // App application
 type App struct {
-	ctx    context.Context
-	config *Config
-	server *http.Server
-	close  func()
+	ctx     context.Context
+	config  *Config
+	servers []*server
+	close   func()
 }
 
 // NewApp establish an APP
 func NewApp(ctx context.Context, c *Config, handler *gin.Engine) *App {
+	return NewAppWithAdmin(ctx, c, handler, nil)
+}
+
+// NewAppWithAdmin establish an APP
+// HTTPServer.Listeners 中 Admin=true 的监听使用 adminHandler, 为空时使用 handler
+func NewAppWithAdmin(ctx context.Context, c *Config, handler *gin.Engine, adminHandler *gin.Engine) *App {
 	ctxRet, cancel := context.WithCancel(ctx)
 	app := &App{
 		ctx:    ctxRet,
 		config: c,
 		close:  cancel,
 	}
-	app.initHTTPServer(handler)
+	app.initHTTPServer(handler, adminHandler)
 	return app
 }
 
-func (app *App) initHTTPServer(handler *gin.Engine) {
+func (app *App) initHTTPServer(handler *gin.Engine, adminHandler *gin.Engine) {
+	mainListener := ListenerConfig{
+		Name:        mainListenerName,
+		Listen:      app.config.HTTPServer.Listen,
+		H2C:         app.config.HTTPServer.H2C,
+		SocketMode:  app.config.HTTPServer.SocketMode,
+		SocketGroup: app.config.HTTPServer.SocketGroup,
+	}
+	app.servers = append(app.servers, app.newServer(mainListener, handler))
+	for _, lc := range app.config.HTTPServer.Listeners {
+		h := handler
+		if lc.Admin && adminHandler != nil {
+			h = adminHandler
+		}
+		app.servers = append(app.servers, app.newServer(lc, h))
+	}
+}
+
+func (app *App) newServer(lc ListenerConfig, handler http.Handler) *server {
 	ser := &http.Server{
-		Addr:         app.config.HTTPServer.Listen,
+		Addr:         lc.Listen,
 		Handler:      handler,
 		ReadTimeout:  time.Millisecond * time.Duration(app.config.HTTPServer.ReadTimeout),
 		WriteTimeout: time.Millisecond * time.Duration(app.config.HTTPServer.WriteTimeout),
 		IdleTimeout:  time.Millisecond * time.Duration(app.config.HTTPServer.IdleTimeout),
+
+		ReadHeaderTimeout: time.Millisecond * time.Duration(app.config.HTTPServer.ReadHeaderTimeout),
+		MaxHeaderBytes:    app.config.HTTPServer.MaxHeaderBytes,
+		ConnState:         metrics.ConnState(lc.Name),
+		// 请求的ctx继承自app, app退出时可感知
+		BaseContext: func(net.Listener) context.Context {
+			return app.ctx
+		},
+	}
+	ser.SetKeepAlivesEnabled(!app.config.HTTPServer.DisableKeepAlives)
+	return &server{
+		ListenerConfig: lc,
+		Server:         ser,
+		maxConns:       app.config.HTTPServer.MaxConns,
+		tcpKeepAlive:   time.Millisecond * time.Duration(app.config.HTTPServer.TCPKeepAlive),
 	}
-	app.server = ser
 }
 
 // Start start the service
+// 主监听为http, 同时启动 HTTPServer.Listeners 中的所有监听
+// 收到 SIGINT/SIGTERM 后优雅退出, 正常退出时返回nil
 func (app *App) Start() error {
-	// start listening to port
-	fmt.Fprintf(DefaultWriter, "[APP START] Listening and serving HTTP on %s\n", app.config.HTTPServer.Listen)
-	// start distribute routers
-	return app.server.ListenAndServe()
+	return app.start(false)
 }
 
 // Start start the https service
+// 主监听为https, 同时启动 HTTPServer.Listeners 中的所有监听
 func (app *App) StartTLS() error {
+	return app.start(true)
+}
+
+func (app *App) start(mainTLS bool) error {
+	app.servers[0].TLS = mainTLS
+	if err := app.checkListeners(); err != nil {
+		app.close()
+		return err
+	}
+	tlsConfig, err := app.tlsConfig()
+	if err != nil {
+		app.close()
+		return err
+	}
 	// start listening to port
-	fmt.Fprintf(DefaultWriter, "[APP START] Listening and serving HTTPS on %s\n", app.config.HTTPServer.Listen)
+	for _, s := range app.servers {
+		if err := s.listen(); err != nil {
+			app.closeListeners()
+			closeUnusedInherited()
+			closeUnusedActivated()
+			app.close()
+			return err
+		}
+	}
+	closeUnusedInherited()
+	closeUnusedActivated()
 	// start distribute routers
-	certFile := filepath.Join(app.config.Env.ConfDir(), appConfCertsDir, CrtFileName)
-	keyFile := filepath.Join(app.config.Env.ConfDir(), appConfCertsDir, KeyFileName)
-	return app.server.ListenAndServeTLS(certFile, keyFile)
+	return app.serve(tlsConfig)
+}
+
+// checkListeners 名字和监听地址都不能重复
+func (app *App) checkListeners() error {
+	names := make(map[string]bool, len(app.servers))
+	addrs := make(map[string]bool, len(app.servers))
+	for _, s := range app.servers {
+		if s.Name == "" {
+			return fmt.Errorf("listener %q has no name", s.Listen)
+		}
+		if s != app.servers[0] && s.Listen == "" {
+			return fmt.Errorf("listener %q has no listen address", s.Name)
+		}
+		if names[s.Name] {
+			return fmt.Errorf("listener name %q is duplicated", s.Name)
+		}
+		if s.H2C && s.TLS {
+			return fmt.Errorf("listener %q: H2C is only for http, https supports HTTP/2 already", s.Name)
+		}
+		if addrs[s.address()] {
+			return fmt.Errorf("listen address %q is duplicated", s.address())
+		}
+		names[s.Name] = true
+		addrs[s.address()] = true
+	}
+	return nil
+}
+
+// closeListeners 启动失败时关闭已经打开的监听
+func (app *App) closeListeners() {
+	for _, s := range app.servers {
+		if s.listener != nil {
+			_ = s.listener.Close()
+		}
+	}
+}
+
+// tlsConfig 任意一个监听为https时, 加载证书
+func (app *App) tlsConfig() (*tls.Config, error) {
+	for _, s := range app.servers {
+		if s.TLS {
+			return newTLSConfig(app.ctx, app.config)
+		}
+	}
+	return nil, nil
 }
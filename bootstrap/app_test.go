@@ -0,0 +1,50 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 22:10:12
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 22:10:12
+ * @Description: app.toml解析测试
+ */
+package bootstrap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseAppConfigOverlays(t *testing.T) {
+	confDir := filepath.Join(t.TempDir(), "conf")
+	if err := os.MkdirAll(confDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"app.toml":       "APPName = \"demo\"\nRunMode = \"release\"\n[HTTPServer]\nListen = \":8080\"\nReadTimeout = 1000\n",
+		"app.debug.toml": "[HTTPServer]\nListen = \":8081\"\n",
+		"app.test.toml":  "[HTTPServer]\nListen = \":8082\"\n",
+		"app.local.toml": "[HTTPServer]\nReadTimeout = 2000\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(confDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	confPath := filepath.Join(confDir, "app.toml")
+
+	c, err := parseAppConfig(confPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.HTTPServer.Listen != ":8080" || c.HTTPServer.ReadTimeout != 2000 || c.Env.RunMode() != "release" {
+		t.Fatalf("release: %+v", c.HTTPServer)
+	}
+
+	// -runmode 决定叠加哪个文件
+	c, err = parseAppConfig(confPath, []string{"RunMode=debug"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.HTTPServer.Listen != ":8081" || c.HTTPServer.ReadTimeout != 2000 || c.APPName != "demo" || c.Env.RunMode() != "debug" {
+		t.Fatalf("debug: %+v", c.HTTPServer)
+	}
+}
@@ -9,9 +9,17 @@ package bootstrap
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 
 	"github.com/liziwei01/simple-boot/library/env"
+	"github.com/liziwei01/simple-boot/library/errs"
+	"github.com/liziwei01/simple-boot/library/lifecycle"
+	"github.com/liziwei01/simple-boot/library/metrics"
+	"github.com/liziwei01/simple-boot/library/router"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,38 +31,174 @@ const (
 // AppServer struct.
 type AppServer struct {
 	Handler *gin.Engine
-	Ctx     context.Context
-	Config  *Config
-	Cancel  context.CancelFunc
+	// Router Handler 上的路由表, 控制器在此声明路由
+	Router router.Router
+	// AdminHandler 内部管理端口使用的 gin engine
+	// 仅当 HTTPServer.Listeners 中配置了 Admin=true 的监听时才会创建
+	AdminHandler *gin.Engine
+	Ctx          context.Context
+	Config       *Config
+	Cancel       context.CancelFunc
+	// Lifecycle 组件的启动、退出回调, 默认为 lifecycle.Default
+	// library中的组件(如mysql、redis)在init时注册到了默认实例
+	Lifecycle lifecycle.Registry
+
+	flags Flags
+	// setupHooks Setup 过程中创建的资源的退出回调, Setup 成功后注册到 Lifecycle
+	setupHooks []lifecycle.Hook
 }
 
+// ErrChecked 指定了 -check 且配置没有问题时 Setup 返回的错误, 应用应当直接退出
+var ErrChecked = errors.New("config checked, not serving")
+
 // Setup 准备.
+// 使用命令行参数及环境变量指定配置, 见 Flags、CommandLine
+// 指定了 -check 时只检查配置, 问题输出到标准输出, 没有问题返回 ErrChecked, 否则返回其他错误
 func Setup() (*AppServer, error) {
-	appServer := &AppServer{}
+	if err := CommandLine.parse(os.Args[1:]); err != nil {
+		return nil, err
+	}
+	f := CommandLine.withEnv()
+	if f.Check {
+		if n := runCheck(f, os.Stdout); n > 0 {
+			return nil, fmt.Errorf("config check: %d problem(s) found", n)
+		}
+		return nil, ErrChecked
+	}
+	return SetupWithFlags(f)
+}
+
+// SetupWithFlags 使用指定的参数准备, 不读取命令行参数及环境变量
+func SetupWithFlags(f Flags) (*AppServer, error) {
+	if f.ConfPath == "" {
+		f.ConfPath = appConfPath
+	}
+	appServer := &AppServer{flags: f}
 	var (
 		err error
 	)
-	appServer.Config, err = ParserAppConfig(appConfPath)
+	appServer.Config, err = parseAppConfig(f.ConfPath, f.Sets)
 	if err != nil {
 		return nil, err
 	}
 	env.Default = appServer.Config.Env
+	// conf/errs/<语言>.toml 中的错误信息本地化
+	if err := errs.LoadMessagesDir(errs.Default, filepath.Join(env.ConfDir(), errsConfDir)); err != nil {
+		return nil, err
+	}
+	appServer.Lifecycle = lifecycle.Default
+	// 连接数等框架指标, 由admin监听的 /metrics 输出
+	if err := metrics.RegisterConnState(metrics.Registerer); err != nil {
+		return nil, err
+	}
 	appServer.Ctx, appServer.Cancel = context.WithCancel(context.Background())
-	appServer.Handler = InitHandler(appServer)
+	if err := appServer.initHandlers(); err != nil {
+		appServer.Cancel()
+		// 中间件创建的资源(如access_log的writer)还没有交给 Lifecycle, 在这里释放
+		appServer.closeSetupHooks()
+		return nil, err
+	}
+	// Setup 成功后才注册, 失败时不会在 Lifecycle 中留下同名的hook
+	for _, h := range appServer.setupHooks {
+		if err := appServer.Lifecycle.Register(h); err != nil {
+			return nil, err
+		}
+	}
 
 	return appServer, nil
 }
 
+// closeSetupHooks 倒序执行 setupHooks 的退出回调
+func (appServer *AppServer) closeSetupHooks() {
+	for i := len(appServer.setupHooks) - 1; i >= 0; i-- {
+		if h := appServer.setupHooks[i]; h.OnStop != nil {
+			if err := h.OnStop(context.Background()); err != nil {
+				log.Printf("setup failed, close %q: %v\n", h.Name, err)
+			}
+		}
+	}
+}
+
+// initHandlers 创建handler, 注册路由及模块
+func (appServer *AppServer) initHandlers() error {
+	var err error
+	appServer.Handler, err = NewHandler(appServer)
+	if err != nil {
+		return err
+	}
+	appServer.Router = router.New(appServer.Handler)
+	if appServer.Config.hasAdminListener() {
+		appServer.AdminHandler = InitAdminHandler(appServer)
+	}
+	registerDebugRoutes(appServer)
+	if err := registerOpenAPIRoutes(appServer); err != nil {
+		return err
+	}
+	return initModules(appServer)
+}
+
 // Start 启动http服务器.
+// 阻塞直到收到退出信号并优雅关闭, 出错时退出进程, 需要错误时使用 Run
 func (appServer *AppServer) Start() {
-	defer appServer.Cancel()
-	app := NewApp(appServer.Ctx, appServer.Config, appServer.Handler)
-	log.Fatalln("server exit:", app.Start())
+	if err := appServer.Run(); err != nil {
+		log.Fatalln("server exit:", err)
+	}
 }
 
 // Start 启动https服务器.
 func (appServer *AppServer) StartTLS() {
+	if err := appServer.RunTLS(); err != nil {
+		log.Fatalln("server exit:", err)
+	}
+}
+
+// Run 启动http服务器, 同 Start, 出错时返回错误而不是退出进程.
+// 阻塞直到收到退出信号并优雅关闭, 返回后 appServer.Ctx 已被取消
+// 指定了 -openapi 时只输出文档, 不启动服务
+func (appServer *AppServer) Run() error {
+	return appServer.run(func(app *App) error {
+		return app.Start()
+	})
+}
+
+// RunTLS 启动https服务器, 同 StartTLS, 出错时返回错误
+func (appServer *AppServer) RunTLS() error {
+	return appServer.run(func(app *App) error {
+		return app.StartTLS()
+	})
+}
+
+// OnStart 注册一个启动回调, 在开始监听端口前按注册顺序执行
+func (appServer *AppServer) OnStart(name string, fn func(ctx context.Context) error) error {
+	return appServer.Lifecycle.Register(lifecycle.Hook{Name: name, OnStart: fn})
+}
+
+// OnStop 注册一个退出回调, 在http服务关闭后倒序执行
+func (appServer *AppServer) OnStop(name string, fn func(ctx context.Context) error) error {
+	return appServer.Lifecycle.Register(lifecycle.Hook{Name: name, OnStop: fn})
+}
+
+// run 先执行所有的启动回调, 再启动服务, 服务退出后执行所有的退出回调
+func (appServer *AppServer) run(start func(app *App) error) error {
 	defer appServer.Cancel()
-	app := NewApp(appServer.Ctx, appServer.Config, appServer.Handler)
-	log.Fatalln("server exit:", app.StartTLS())
+	// 所有路由已经注册, 只输出文档
+	if path := appServer.flags.OpenAPI; path != "" {
+		return appServer.writeOpenAPI(path)
+	}
+	if err := appServer.Lifecycle.Start(appServer.Ctx); err != nil {
+		return logExit(err)
+	}
+	app := NewAppWithAdmin(appServer.Ctx, appServer.Config, appServer.Handler, appServer.AdminHandler)
+	errStart := start(app)
+	// 退出回调不能使用已取消的ctx
+	errStop := appServer.Lifecycle.Stop(context.Background())
+	return logExit(errors.Join(errStart, errStop))
+}
+
+// logExit 正常退出时输出日志, 错误由调用方处理, 如 Start 输出后退出进程
+func logExit(err error) error {
+	if err == nil {
+		log.Println("server exit")
+	}
+	return err
 }
@@ -0,0 +1,240 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 19:32:10
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 19:32:10
+ * @Description: 使用 httptest 测试 bootstrap 应用
+ */
+
+// Package bootstraptest 在临时目录中使用内存中的配置创建 AppServer,
+// 注入假的mysql、redis、oss客户端, 在 httptest.Server 上运行, 测试结束后恢复全局状态
+//
+//	app := bootstraptest.New(t, bootstraptest.Options{
+//		Redis: map[string]redis.Client{"db_redis": bootstraptest.NewFakeRedis()},
+//	})
+//	app.Handler.GET("/ping", ping)
+//	resp, body := app.Get(t, "/ping")
+package bootstraptest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/liziwei01/simple-boot/bootstrap"
+	"github.com/liziwei01/simple-boot/library/env"
+	"github.com/liziwei01/simple-boot/library/errs"
+	"github.com/liziwei01/simple-boot/library/health"
+	"github.com/liziwei01/simple-boot/library/lifecycle"
+	"github.com/liziwei01/simple-boot/library/mysql"
+	"github.com/liziwei01/simple-boot/library/oss"
+	"github.com/liziwei01/simple-boot/library/redis"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultAppConf Options.AppConf 为空时使用的配置
+const DefaultAppConf = `
+APPName = "bootstraptest"
+RunMode = "test"
+
+[HTTPServer]
+Listen = "127.0.0.1:0"
+`
+
+// Options 测试应用的配置
+type Options struct {
+	// AppConf conf/app.toml 的内容, 为空时使用 DefaultAppConf
+	AppConf string
+	// Files 其他配置文件, key为相对于conf目录的路径, 如 servicer/db_redis.toml
+	Files map[string]string
+	// Sets 覆盖配置, 同命令行参数 -set
+	Sets []string
+
+	// Modules、Middlewares 只在该测试中注册, 测试结束时移除
+	// 直接调用 bootstrap.RegisterModule 等注册的会保留到之后的测试
+	Modules     []bootstrap.Module
+	Middlewares map[string]bootstrap.MiddlewareFactory
+
+	// 按服务名注入的假客户端
+	MySQL map[string]mysql.Client
+	Redis map[string]redis.Client
+	OSS   map[string]oss.Client
+
+	// TLS 使用 httptest.NewTLSServer, 证书由 httptest 生成
+	TLS bool
+
+	// Setup 在执行启动回调前调用, 用于注册路由及 OnStart、OnStop 回调
+	Setup func(app *bootstrap.AppServer) error
+}
+
+// App 运行在 httptest.Server 上的应用
+// 启动后仍可在 Handler 上注册路由, 但需要在发出请求前完成; 回调需要在 Options.Setup 中注册
+type App struct {
+	*bootstrap.AppServer
+	// Server 运行 Handler 的测试服务器
+	Server *httptest.Server
+	// RootDir 应用的根目录, 即 env.RootDir()
+	RootDir string
+}
+
+// New 创建并启动测试应用, 执行所有的启动回调
+// 测试结束时关闭服务器、执行退出回调(包括关闭mysql、redis的连接池), 并恢复 env、lifecycle、health、errs 的默认实例, gin的mode, 注册的模块、中间件及各客户端
+func New(t testing.TB, opt Options) *App {
+	t.Helper()
+	root := t.TempDir()
+	appConf := opt.AppConf
+	if appConf == "" {
+		appConf = DefaultAppConf
+	}
+	writeFile(t, filepath.Join(root, "conf", "app.toml"), appConf)
+	for name, content := range opt.Files {
+		writeFile(t, filepath.Join(root, "conf", filepath.FromSlash(name)), content)
+	}
+
+	restoreGlobals(t)
+	// 复制library在init时注册的hook(如mysql、redis退出时关闭连接池), 依赖它们的模块可以正常启动,
+	// 测试结束时关闭测试期间打开的连接池; 测试中注册的hook不影响原来的实例
+	lifecycle.Default = lifecycle.Default.Clone()
+	health.Default = health.New()
+	// 保留 errs.Define 定义的错误码, conf/errs 中的信息只加载到复制的实例
+	errs.Default = errs.Default.Clone()
+	for _, m := range opt.Modules {
+		if err := bootstrap.RegisterModule(m); err != nil {
+			t.Fatalf("bootstraptest: %v", err)
+		}
+	}
+	for name, factory := range opt.Middlewares {
+		if err := bootstrap.RegisterMiddleware(name, factory); err != nil {
+			t.Fatalf("bootstraptest: %v", err)
+		}
+	}
+	for name, c := range opt.MySQL {
+		t.Cleanup(mysql.SetClient(name, c))
+	}
+	for name, c := range opt.Redis {
+		t.Cleanup(redis.SetClient(name, c))
+	}
+	for name, c := range opt.OSS {
+		t.Cleanup(oss.SetClient(name, c))
+	}
+
+	appServer, err := bootstrap.SetupWithFlags(bootstrap.Flags{
+		ConfPath: filepath.Join(root, "conf", "app.toml"),
+		Sets:     opt.Sets,
+	})
+	if err != nil {
+		t.Fatalf("bootstraptest: setup: %v", err)
+	}
+	t.Cleanup(appServer.Cancel)
+	if opt.Setup != nil {
+		if err := opt.Setup(appServer); err != nil {
+			t.Fatalf("bootstraptest: setup: %v", err)
+		}
+	}
+	if err := appServer.Lifecycle.Start(appServer.Ctx); err != nil {
+		t.Fatalf("bootstraptest: start: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := appServer.Lifecycle.Stop(context.Background()); err != nil {
+			t.Errorf("bootstraptest: stop: %v", err)
+		}
+	})
+
+	app := &App{
+		AppServer: appServer,
+		RootDir:   root,
+	}
+	if opt.TLS {
+		app.Server = httptest.NewTLSServer(appServer.Handler)
+	} else {
+		app.Server = httptest.NewServer(appServer.Handler)
+	}
+	// 先关闭服务器, 再执行退出回调
+	t.Cleanup(app.Server.Close)
+	return app
+}
+
+// URL 请求path使用的完整地址
+func (app *App) URL(path string) string {
+	return app.Server.URL + path
+}
+
+// Client 请求测试服务器使用的客户端, TLS时信任 httptest 生成的证书
+func (app *App) Client() *http.Client {
+	return app.Server.Client()
+}
+
+// Do 发送请求, 返回响应及读取的body, 出错时测试失败
+// body为空时不发送body, 以{或[开头时Content-Type为application/json
+func (app *App) Do(t testing.TB, method string, path string, body string, header ...http.Header) (*http.Response, []byte) {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, app.URL(path), reader)
+	if err != nil {
+		t.Fatalf("bootstraptest: new request: %v", err)
+	}
+	if strings.HasPrefix(body, "{") || strings.HasPrefix(body, "[") {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for _, h := range header {
+		for k, v := range h {
+			req.Header[k] = v
+		}
+	}
+	resp, err := app.Client().Do(req)
+	if err != nil {
+		t.Fatalf("bootstraptest: %s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("bootstraptest: read body: %v", err)
+	}
+	return resp, data
+}
+
+// Get 发送GET请求
+func (app *App) Get(t testing.TB, path string) (*http.Response, []byte) {
+	t.Helper()
+	return app.Do(t, http.MethodGet, path, "")
+}
+
+// Post 发送POST请求
+func (app *App) Post(t testing.TB, path string, body string) (*http.Response, []byte) {
+	t.Helper()
+	return app.Do(t, http.MethodPost, path, body)
+}
+
+// restoreGlobals 测试结束后恢复 SetupWithFlags 及 New 修改的全局变量
+func restoreGlobals(t testing.TB) {
+	envDefault, lifecycleDefault, healthDefault, errsDefault := env.Default, lifecycle.Default, health.Default, errs.Default
+	ginMode := gin.Mode()
+	restoreRegistries := bootstrap.SaveRegistries()
+	t.Cleanup(func() {
+		env.Default = envDefault
+		lifecycle.Default = lifecycleDefault
+		health.Default = healthDefault
+		errs.Default = errsDefault
+		gin.SetMode(ginMode)
+		restoreRegistries()
+	})
+}
+
+func writeFile(t testing.TB, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("bootstraptest: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("bootstraptest: %v", err)
+	}
+}
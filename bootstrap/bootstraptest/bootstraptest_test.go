@@ -0,0 +1,236 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 19:32:10
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 19:32:10
+ * @Description: bootstraptest 测试
+ */
+package bootstraptest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	r "github.com/go-redis/redis"
+	"github.com/liziwei01/simple-boot/bootstrap"
+	"github.com/liziwei01/simple-boot/library/env"
+	"github.com/liziwei01/simple-boot/library/errs"
+	"github.com/liziwei01/simple-boot/library/lifecycle"
+	"github.com/liziwei01/simple-boot/library/mysql"
+	"github.com/liziwei01/simple-boot/library/oss"
+	"github.com/liziwei01/simple-boot/library/redis"
+	"github.com/liziwei01/simple-boot/library/router"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestApp(t *testing.T) {
+	rootBefore := env.RootDir()
+	var stopped bool
+	t.Run("app", func(t *testing.T) {
+		app := New(t, Options{
+			Sets:  []string{"APPName=demo"},
+			Files: map[string]string{"servicer/db_redis.toml": `Name = "db_redis"`},
+			Redis: map[string]redis.Client{"db_redis": NewFakeRedis()},
+			Setup: func(app *bootstrap.AppServer) error {
+				return app.OnStop("test", func(ctx context.Context) error {
+					stopped = true
+					return nil
+				})
+			},
+		})
+		if env.RootDir() != app.RootDir || env.AppName() != "demo" {
+			t.Fatalf("env not set: root=%s app=%s", env.RootDir(), env.AppName())
+		}
+		if got := filepath.Join(env.ConfDir(), "servicer", "db_redis.toml"); !fileExists(got) {
+			t.Fatalf("%s not written", got)
+		}
+		app.Handler.POST("/counter", func(c *gin.Context) {
+			client, err := redis.GetClient(c, "db_redis")
+			if err != nil {
+				c.String(http.StatusInternalServerError, err.Error())
+				return
+			}
+			if _, err := client.Get(c, "counter"); err != r.Nil {
+				c.String(http.StatusConflict, "counter exists")
+				return
+			}
+			_ = client.Set(c, "counter", "1")
+			c.String(http.StatusOK, "created")
+		})
+
+		resp, body := app.Post(t, "/counter", "")
+		if resp.StatusCode != http.StatusOK || string(body) != "created" {
+			t.Fatalf("first: %d %s", resp.StatusCode, body)
+		}
+		resp, _ = app.Post(t, "/counter", "")
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("second: %d", resp.StatusCode)
+		}
+		resp, _ = app.Get(t, "/healthz")
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("healthz: %d", resp.StatusCode)
+		}
+	})
+
+	if !stopped {
+		t.Fatal("stop hooks not run")
+	}
+	if env.RootDir() != rootBefore {
+		t.Fatalf("env not restored: %s", env.RootDir())
+	}
+	if _, err := redis.GetClient(context.Background(), "db_redis"); err == nil {
+		t.Fatal("fake redis client not removed")
+	}
+}
+
+func TestRestoreGlobals(t *testing.T) {
+	e := &errs.Error{Code: 99999, Message: "restore"}
+	t.Run("app", func(t *testing.T) {
+		New(t, Options{
+			Files:   map[string]string{"errs/zh.toml": `99999 = "恢复"`},
+			Modules: []bootstrap.Module{{Name: "restore"}},
+			Middlewares: map[string]bootstrap.MiddlewareFactory{
+				"restore": func(app *bootstrap.AppServer) (gin.HandlerFunc, error) {
+					return func(c *gin.Context) {}, nil
+				},
+			},
+		})
+		if got := errs.Default.Localize(e, "zh"); got != "恢复" {
+			t.Fatalf("messages not loaded: %q", got)
+		}
+	})
+	if got := errs.Default.Localize(e, "zh"); got != "restore" {
+		t.Fatalf("errs.Default not restored: %q", got)
+	}
+	defer bootstrap.SaveRegistries()()
+	if err := bootstrap.RegisterModule(bootstrap.Module{Name: "restore"}); err != nil {
+		t.Fatalf("modules not restored: %v", err)
+	}
+	if err := bootstrap.RegisterMiddleware("restore", func(app *bootstrap.AppServer) (gin.HandlerFunc, error) {
+		return nil, nil
+	}); err != nil {
+		t.Fatalf("middlewares not restored: %v", err)
+	}
+}
+
+func TestFakes(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+	rds := NewFakeRedis()
+	rds.now = func() time.Time { return now }
+	_ = rds.Set(ctx, "a", "1", time.Second)
+	_ = rds.Set(ctx, "b", "2", 0)
+	if n, _ := rds.Exists(ctx, "a", "b", "c"); n != 2 {
+		t.Fatalf("exists=%d", n)
+	}
+	now = now.Add(time.Second)
+	if _, err := rds.Get(ctx, "a"); err != r.Nil {
+		t.Fatalf("a should be expired, err=%v", err)
+	}
+	if v, _ := rds.Get(ctx, "b"); v != "2" {
+		t.Fatalf("b=%q", v)
+	}
+
+	var db mysql.Client = &FakeMySQL{}
+	if err := mysql.QueryWithBuilder(ctx, db, mysql.NewRawBuilder("SELECT 1", nil), nil); !errors.Is(err, mysql.ErrNotRealClient) {
+		t.Fatalf("builder on fake mysql: %v", err)
+	}
+
+	var store oss.Client = NewFakeOSS()
+	if err := store.Put(ctx, "bucket", "x.txt", bytes.NewReader([]byte("hello"))); err != nil {
+		t.Fatal(err)
+	}
+	reader, err := store.Get(ctx, "bucket", "x.txt")
+	if err != nil || reader.Len() != 5 {
+		t.Fatalf("get: %v", err)
+	}
+	_ = store.Del(ctx, "bucket", "x.txt")
+	if _, err := store.Get(ctx, "bucket", "x.txt"); err != ErrNotFound {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestLibraryHooks(t *testing.T) {
+	app := New(t, Options{
+		Files: map[string]string{"servicer/db_user.toml": `
+Name = "db_user"
+[Resource.Manual]
+Host = "127.0.0.1"
+Port = 3306
+`},
+		Redis: map[string]redis.Client{"db_redis": NewFakeRedis()},
+		Setup: func(app *bootstrap.AppServer) error {
+			// 依赖library在init时注册的hook
+			return app.Lifecycle.Register(lifecycle.Hook{Name: "user", DependsOn: []string{"mysql", "redis"}})
+		},
+	})
+	// 创建真实的客户端时注册了健康检查, 之后替换为假客户端, 检查及退出时的关闭都跳过假客户端
+	if _, err := mysql.GetClient(context.Background(), "db_user"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(mysql.SetClient("db_user", &FakeMySQL{}))
+	resp, body := app.Get(t, "/readyz")
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "mysql.db_user") {
+		t.Fatalf("readyz: %d %s", resp.StatusCode, body)
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestOpenAPI(t *testing.T) {
+	type echoReq struct {
+		Name string `json:"name" binding:"required"`
+	}
+	app := New(t, Options{
+		AppConf: DefaultAppConf + `
+[HTTPServer.OpenAPI]
+Enabled = true
+Version = "2.0"
+`,
+		Setup: func(app *bootstrap.AppServer) error {
+			app.Router.Register(router.POST("/echo", func(ctx context.Context, req *echoReq) (*echoReq, error) {
+				return req, nil
+			}))
+			return nil
+		},
+	})
+	resp, body := app.Get(t, "/openapi.json")
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), `"/echo"`) || !strings.Contains(string(body), `"version": "2.0"`) {
+		t.Fatalf("openapi.json: %d %s", resp.StatusCode, body)
+	}
+	resp, body = app.Get(t, "/openapi.yaml")
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "title: bootstraptest\n") {
+		t.Fatalf("openapi.yaml: %d %s", resp.StatusCode, body)
+	}
+	resp, body = app.Post(t, "/echo", `{"name":"amy"}`)
+	if resp.StatusCode != http.StatusOK || string(body) != `{"code":0,"message":"ok","data":{"name":"amy"}}` {
+		t.Fatalf("echo: %d %s", resp.StatusCode, body)
+	}
+}
+
+func TestDebugToken(t *testing.T) {
+	app := New(t, Options{Sets: []string{"HTTPServer.Debug.Enabled=true"}})
+	if resp, _ := app.Get(t, "/debug/buildinfo"); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("debug routes without token: %d", resp.StatusCode)
+	}
+
+	app = New(t, Options{Sets: []string{"HTTPServer.Debug.Enabled=true", "HTTPServer.Debug.Token=secret"}})
+	if resp, _ := app.Get(t, "/debug/buildinfo?token=secret"); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("query token: %d", resp.StatusCode)
+	}
+	resp, _ := app.Do(t, http.MethodGet, "/debug/buildinfo", "", http.Header{"X-Debug-Token": {"secret"}})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("header token: %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,239 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 19:32:10
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 19:32:10
+ * @Description: 假的mysql、redis、oss客户端
+ */
+package bootstraptest
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	r "github.com/go-redis/redis"
+	"github.com/liziwei01/simple-boot/library/mysql"
+	"github.com/liziwei01/simple-boot/library/oss"
+	"github.com/liziwei01/simple-boot/library/redis"
+)
+
+// ErrNotImplemented FakeMySQL 中没有设置对应方法时返回
+var ErrNotImplemented = errors.New("bootstraptest: not implemented")
+
+// ErrNotFound FakeOSS 中对象不存在时返回
+var ErrNotFound = errors.New("bootstraptest: object not found")
+
+// FakeMySQL 由测试设置每个方法的行为, 没有设置的方法返回 ErrNotImplemented
+// 嵌入的 mysql.Client 为nil, 仅用于满足接口中未导出的方法
+// 这些方法只在真实客户端内部调用, 退出时的关闭及健康检查会跳过假客户端,
+// mysql.QueryWithBuilder 等直接使用连接池的函数返回 mysql.ErrNotRealClient
+type FakeMySQL struct {
+	mysql.Client
+
+	QueryFunc             func(ctx context.Context, tableName string, where map[string]interface{}, columns []string, data interface{}) error
+	InsertFunc            func(ctx context.Context, tableName string, data []map[string]interface{}) (sql.Result, error)
+	InsertIgnoreFunc      func(ctx context.Context, tableName string, data []map[string]interface{}) (sql.Result, error)
+	InsertReplaceFunc     func(ctx context.Context, tableName string, data []map[string]interface{}) (sql.Result, error)
+	InsertOnDuplicateFunc func(ctx context.Context, tableName string, data []map[string]interface{}, update map[string]interface{}) (sql.Result, error)
+	UpdateFunc            func(ctx context.Context, tableName string, where map[string]interface{}, update map[string]interface{}) (sql.Result, error)
+	DeleteFunc            func(ctx context.Context, tableName string, where map[string]interface{}) (sql.Result, error)
+	ExecRawFunc           func(ctx context.Context, sql string, args ...interface{}) (sql.Result, error)
+}
+
+func (f *FakeMySQL) Query(ctx context.Context, tableName string, where map[string]interface{}, columns []string, data interface{}) error {
+	if f.QueryFunc == nil {
+		return ErrNotImplemented
+	}
+	return f.QueryFunc(ctx, tableName, where, columns, data)
+}
+
+func (f *FakeMySQL) Insert(ctx context.Context, tableName string, data []map[string]interface{}) (sql.Result, error) {
+	if f.InsertFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.InsertFunc(ctx, tableName, data)
+}
+
+func (f *FakeMySQL) InsertIgnore(ctx context.Context, tableName string, data []map[string]interface{}) (sql.Result, error) {
+	if f.InsertIgnoreFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.InsertIgnoreFunc(ctx, tableName, data)
+}
+
+func (f *FakeMySQL) InsertReplace(ctx context.Context, tableName string, data []map[string]interface{}) (sql.Result, error) {
+	if f.InsertReplaceFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.InsertReplaceFunc(ctx, tableName, data)
+}
+
+func (f *FakeMySQL) InsertOnDuplicate(ctx context.Context, tableName string, data []map[string]interface{}, update map[string]interface{}) (sql.Result, error) {
+	if f.InsertOnDuplicateFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.InsertOnDuplicateFunc(ctx, tableName, data, update)
+}
+
+func (f *FakeMySQL) Update(ctx context.Context, tableName string, where map[string]interface{}, update map[string]interface{}) (sql.Result, error) {
+	if f.UpdateFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.UpdateFunc(ctx, tableName, where, update)
+}
+
+func (f *FakeMySQL) Delete(ctx context.Context, tableName string, where map[string]interface{}) (sql.Result, error) {
+	if f.DeleteFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.DeleteFunc(ctx, tableName, where)
+}
+
+func (f *FakeMySQL) ExecRaw(ctx context.Context, sql string, args ...interface{}) (sql.Result, error) {
+	if f.ExecRawFunc == nil {
+		return nil, ErrNotImplemented
+	}
+	return f.ExecRawFunc(ctx, sql, args...)
+}
+
+// FakeRedis 内存中的redis, 支持过期时间, key不存在时 Get 返回 redis.Nil 同真实客户端
+type FakeRedis struct {
+	redis.Client
+
+	mu    sync.Mutex
+	items map[string]fakeRedisItem
+	// 用于测试过期
+	now func() time.Time
+}
+
+type fakeRedisItem struct {
+	value    string
+	expireAt time.Time
+}
+
+// NewFakeRedis 创建一个空的 FakeRedis
+func NewFakeRedis() *FakeRedis {
+	return &FakeRedis{
+		items: make(map[string]fakeRedisItem),
+		now:   time.Now,
+	}
+}
+
+func (f *FakeRedis) Get(ctx context.Context, key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	item, ok := f.get(key)
+	if !ok {
+		return "", r.Nil
+	}
+	return item.value, nil
+}
+
+// Set 同真实客户端, 默认过期时间为1小时, 为0时不过期
+func (f *FakeRedis) Set(ctx context.Context, key string, value string, expireTime ...time.Duration) error {
+	exp := time.Hour
+	if len(expireTime) > 0 {
+		exp = expireTime[0]
+	}
+	item := fakeRedisItem{value: value}
+	if exp > 0 {
+		item.expireAt = f.now().Add(exp)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.items[key] = item
+	return nil
+}
+
+func (f *FakeRedis) Del(ctx context.Context, keys ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, key := range keys {
+		delete(f.items, key)
+	}
+	return nil
+}
+
+func (f *FakeRedis) Exists(ctx context.Context, keys ...string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var n int64
+	for _, key := range keys {
+		if _, ok := f.get(key); ok {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// get 需要持有锁, 过期的key在读取时删除
+func (f *FakeRedis) get(key string) (fakeRedisItem, bool) {
+	item, ok := f.items[key]
+	if !ok {
+		return item, false
+	}
+	if !item.expireAt.IsZero() && !f.now().Before(item.expireAt) {
+		delete(f.items, key)
+		return item, false
+	}
+	return item, true
+}
+
+// FakeOSS 内存中的oss, 对象按bucket和objectKey存放
+type FakeOSS struct {
+	oss.Client
+
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+// NewFakeOSS 创建一个空的 FakeOSS
+func NewFakeOSS() *FakeOSS {
+	return &FakeOSS{
+		objects: make(map[string][]byte),
+	}
+}
+
+func (f *FakeOSS) Get(ctx context.Context, bucket string, objectKey string) (*bytes.Reader, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, ok := f.objects[bucket+"/"+objectKey]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return bytes.NewReader(data), nil
+}
+
+func (f *FakeOSS) Put(ctx context.Context, bucket string, objectKey string, fileReader *bytes.Reader) error {
+	var buf bytes.Buffer
+	if _, err := fileReader.WriteTo(&buf); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.objects[bucket+"/"+objectKey] = buf.Bytes()
+	return nil
+}
+
+func (f *FakeOSS) Del(ctx context.Context, bucket string, objectKey string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.objects, bucket+"/"+objectKey)
+	return nil
+}
+
+// GetURL 返回 fake:// 开头的地址, 对象不需要存在
+func (f *FakeOSS) GetURL(ctx context.Context, bucket string, objectKey string) (string, error) {
+	return "fake://" + bucket + "/" + objectKey, nil
+}
+
+// 为了在编译期即确保实现了接口
+var (
+	_ mysql.Client = (*FakeMySQL)(nil)
+	_ redis.Client = (*FakeRedis)(nil)
+	_ oss.Client   = (*FakeOSS)(nil)
+)
@@ -0,0 +1,285 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 14:21:09
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 14:21:09
+ * @Description: https证书, 支持按SNI选择证书及证书文件热更新
+ */
+package bootstrap
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/liziwei01/simple-boot/library/env"
+)
+
+const (
+	certFileExt = ".crt"
+	keyFileExt  = ".key"
+	// 默认检查证书文件变化的间隔
+	defaultCertReloadInterval = 10 * time.Second
+)
+
+// TLSConfig https的配置
+type TLSConfig struct {
+	// CertsDir 证书目录, 默认为 conf/certs, 相对路径相对于conf目录
+	// 目录下所有的 xxx.crt 与 xxx.key 成对加载, 按SNI选择证书, server.crt 为默认证书
+	CertsDir string
+	// ReloadInterval 检查证书文件变化的间隔, 默认10s, 小于0不检查
+	ReloadInterval int // ms
+	// ClientCAFile 校验客户端证书(mTLS)的CA文件, 为空则不校验, 相对路径相对于conf目录
+	ClientCAFile string
+	// ClientAuth 客户端证书的校验方式, 可选:
+	// none、request、require、verify_if_given、require_and_verify
+	// 配置了ClientCAFile时默认为 require_and_verify
+	ClientAuth string
+}
+
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	"none":               tls.NoClientCert,
+	"request":            tls.RequestClientCert,
+	"require":            tls.RequireAnyClientCert,
+	"verify_if_given":    tls.VerifyClientCertIfGiven,
+	"require_and_verify": tls.RequireAndVerifyClientCert,
+}
+
+// CertProvider 加载目录下所有的证书, 作为 tls.Config.GetCertificate 使用
+type CertProvider struct {
+	dir string
+
+	mu          sync.RWMutex
+	byName      map[string]*tls.Certificate
+	defaultCert *tls.Certificate
+	// 证书文件的修改时间及大小, 用于判断是否需要重新加载
+	signature string
+}
+
+// NewCertProvider 从dir加载所有成对的 .crt .key 证书
+func NewCertProvider(dir string) (*CertProvider, error) {
+	p := &CertProvider{
+		dir: dir,
+	}
+	if err := p.Load(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Load 重新加载所有证书, 失败时保留之前加载的证书
+func (p *CertProvider) Load() error {
+	signature, err := p.dirSignature()
+	if err != nil {
+		return err
+	}
+	crtFiles, err := filepath.Glob(filepath.Join(p.dir, "*"+certFileExt))
+	if err != nil {
+		return err
+	}
+	sort.Strings(crtFiles)
+	if len(crtFiles) == 0 {
+		return fmt.Errorf("no %s file found in %q", certFileExt, p.dir)
+	}
+
+	byName := make(map[string]*tls.Certificate)
+	var defaultCert *tls.Certificate
+	for _, crtFile := range crtFiles {
+		keyFile := strings.TrimSuffix(crtFile, certFileExt) + keyFileExt
+		cert, err := tls.LoadX509KeyPair(crtFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("load %q: %w", crtFile, err)
+		}
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return fmt.Errorf("parse %q: %w", crtFile, err)
+		}
+		cert.Leaf = leaf
+		for _, name := range certNames(leaf) {
+			// 先加载的优先
+			if _, has := byName[name]; !has {
+				byName[name] = &cert
+			}
+		}
+		if defaultCert == nil || filepath.Base(crtFile) == CrtFileName {
+			defaultCert = &cert
+		}
+	}
+
+	p.mu.Lock()
+	p.byName = byName
+	p.defaultCert = defaultCert
+	p.signature = signature
+	p.mu.Unlock()
+	return nil
+}
+
+// GetCertificate 按SNI选择证书, 没有SNI(如使用IP访问)时按连接的本地IP选择, 没有匹配的返回默认证书
+func (p *CertProvider) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	name := strings.ToLower(strings.TrimSuffix(hello.ServerName, "."))
+	if name == "" && hello.Conn != nil {
+		if addr, ok := hello.Conn.LocalAddr().(*net.TCPAddr); ok {
+			name = addr.IP.String()
+		}
+	}
+	if name != "" {
+		if cert, has := p.byName[name]; has {
+			return cert, nil
+		}
+		// 通配符证书 *.example.com
+		if idx := strings.Index(name, "."); idx > 0 && net.ParseIP(name) == nil {
+			if cert, has := p.byName["*"+name[idx:]]; has {
+				return cert, nil
+			}
+		}
+	}
+	if p.defaultCert == nil {
+		return nil, errors.New("no certificate loaded")
+	}
+	return p.defaultCert, nil
+}
+
+// Watch 定期检查证书文件, 有变化时重新加载, 直到ctx结束
+func (p *CertProvider) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		signature, err := p.dirSignature()
+		if err != nil {
+			log.Printf("[certs] check %q has error: %v\n", p.dir, err)
+			continue
+		}
+		p.mu.RLock()
+		changed := signature != p.signature
+		p.mu.RUnlock()
+		if !changed {
+			continue
+		}
+		if err := p.Load(); err != nil {
+			log.Printf("[certs] reload %q has error, keep using old certificates: %v\n", p.dir, err)
+			continue
+		}
+		log.Printf("[certs] reload %q success\n", p.dir)
+	}
+}
+
+// dirSignature 目录下所有证书文件的名字、大小、修改时间
+func (p *CertProvider) dirSignature() (string, error) {
+	entries, err := os.ReadDir(p.dir)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if ext != certFileExt && ext != keyFileExt {
+			continue
+		}
+		// 软连接需要取目标文件的信息, 如 k8s secret 的挂载
+		info, err := os.Stat(filepath.Join(p.dir, entry.Name()))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s|%d|%d;", entry.Name(), info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
+
+// certNames 证书可用于的域名及IP
+func certNames(leaf *x509.Certificate) []string {
+	names := make([]string, 0, len(leaf.DNSNames)+len(leaf.IPAddresses)+1)
+	for _, name := range leaf.DNSNames {
+		names = append(names, strings.ToLower(name))
+	}
+	for _, ip := range leaf.IPAddresses {
+		names = append(names, ip.String())
+	}
+	if len(names) == 0 && leaf.Subject.CommonName != "" {
+		names = append(names, strings.ToLower(leaf.Subject.CommonName))
+	}
+	return names
+}
+
+// newTLSConfig 根据配置创建https使用的tls.Config, 证书文件变化时自动重新加载, 直到ctx结束
+func newTLSConfig(ctx context.Context, c *Config) (*tls.Config, error) {
+	tc := c.HTTPServer.TLS
+	certsDir := confRelPath(c, tc.CertsDir, appConfCertsDir)
+	// debug模式下没有证书时, 使用自签名证书
+	if c.Env.RunMode() == env.RunModeDebug && !hasCertFile(certsDir) {
+		certsDir = filepath.Join(c.Env.DataDir(), devCertsDir)
+		if err := ensureDevCert(certsDir); err != nil {
+			return nil, fmt.Errorf("generate self-signed certificate: %w", err)
+		}
+		log.Printf("[certs] no certificate found, using self-signed certificate in %q\n", certsDir)
+	}
+	provider, err := NewCertProvider(certsDir)
+	if err != nil {
+		return nil, err
+	}
+	interval := defaultCertReloadInterval
+	if tc.ReloadInterval > 0 {
+		interval = time.Millisecond * time.Duration(tc.ReloadInterval)
+	}
+	if tc.ReloadInterval >= 0 {
+		go provider.Watch(ctx, interval)
+	}
+
+	tlsConfig := &tls.Config{
+		GetCertificate: provider.GetCertificate,
+	}
+	if tc.ClientCAFile != "" {
+		caFile := confRelPath(c, tc.ClientCAFile, "")
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in ClientCAFile %q", caFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	if tc.ClientAuth != "" {
+		authType, has := clientAuthTypes[tc.ClientAuth]
+		if !has {
+			return nil, fmt.Errorf("ClientAuth %q not supported", tc.ClientAuth)
+		}
+		tlsConfig.ClientAuth = authType
+	}
+	return tlsConfig, nil
+}
+
+// hasCertFile 目录下是否有证书文件
+func hasCertFile(dir string) bool {
+	matches, _ := filepath.Glob(filepath.Join(dir, "*"+certFileExt))
+	return len(matches) > 0
+}
+
+// confRelPath 相对路径视为相对于conf目录, 为空时使用defaultName
+func confRelPath(c *Config, path string, defaultName string) string {
+	if path == "" {
+		path = defaultName
+	}
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(c.Env.ConfDir(), path)
+}
@@ -0,0 +1,97 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-19 10:31:22
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-19 10:31:22
+ * @Description: 证书选择及热更新测试
+ */
+package bootstrap
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// localAddrConn 只用于提供本地地址
+type localAddrConn struct {
+	net.Conn
+	addr net.Addr
+}
+
+func (c localAddrConn) LocalAddr() net.Addr {
+	return c.addr
+}
+
+func writeTestCert(t *testing.T, dir string, name string, hosts ...string) {
+	t.Helper()
+	if err := generateSelfSignedCert(filepath.Join(dir, name+certFileExt), filepath.Join(dir, name+keyFileExt), hosts); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCertProvider(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewCertProvider(dir); err == nil {
+		t.Fatal("empty dir should fail")
+	}
+	writeTestCert(t, dir, "server", "localhost")
+	writeTestCert(t, dir, "a", "a.example.com")
+	writeTestCert(t, dir, "wild", "*.example.com")
+	writeTestCert(t, dir, "ip", "10.1.2.3")
+	p, err := NewCertProvider(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leafName := func(hello *tls.ClientHelloInfo) string {
+		t.Helper()
+		cert, err := p.GetCertificate(hello)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cert.Leaf.DNSNames) > 0 {
+			return cert.Leaf.DNSNames[0]
+		}
+		return cert.Leaf.IPAddresses[0].String()
+	}
+	ipConn := localAddrConn{addr: &net.TCPAddr{IP: net.ParseIP("10.1.2.3"), Port: 443}}
+	cases := []struct {
+		hello *tls.ClientHelloInfo
+		want  string
+	}{
+		{&tls.ClientHelloInfo{ServerName: "a.example.com"}, "a.example.com"},
+		{&tls.ClientHelloInfo{ServerName: "A.Example.com."}, "a.example.com"},
+		{&tls.ClientHelloInfo{ServerName: "b.example.com"}, "*.example.com"},
+		{&tls.ClientHelloInfo{ServerName: "x.b.example.com"}, "localhost"},
+		{&tls.ClientHelloInfo{ServerName: "other.org"}, "localhost"},
+		{&tls.ClientHelloInfo{}, "localhost"},
+		// 没有SNI时按本地IP
+		{&tls.ClientHelloInfo{Conn: ipConn}, "10.1.2.3"},
+		{&tls.ClientHelloInfo{ServerName: "a.example.com", Conn: ipConn}, "a.example.com"},
+	}
+	for _, tc := range cases {
+		if got := leafName(tc.hello); got != tc.want {
+			t.Errorf("%q: got %q, want %q", tc.hello.ServerName, got, tc.want)
+		}
+	}
+
+	// 证书文件变化后自动重新加载
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Watch(ctx, 10*time.Millisecond)
+	writeTestCert(t, dir, "a", "new.org")
+	deadline := time.Now().Add(3 * time.Second)
+	for leafName(&tls.ClientHelloInfo{ServerName: "new.org"}) != "new.org" {
+		if time.Now().After(deadline) {
+			t.Fatal("certificate not reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := leafName(&tls.ClientHelloInfo{ServerName: "a.example.com"}); got != "*.example.com" {
+		t.Errorf("after reload a.example.com: %q", got)
+	}
+}
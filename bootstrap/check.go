@@ -0,0 +1,229 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 17:52:36
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 17:52:36
+ * @Description: -check 检查配置文件
+ */
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/liziwei01/simple-boot/library/conf"
+	"github.com/liziwei01/simple-boot/library/env"
+	"github.com/liziwei01/simple-boot/library/errs"
+	"github.com/liziwei01/simple-boot/library/middleware"
+	"github.com/liziwei01/simple-boot/library/mysql"
+	"github.com/liziwei01/simple-boot/library/oss"
+	"github.com/liziwei01/simple-boot/library/redis"
+)
+
+const (
+	// 下游服务配置的目录, 相对于conf目录
+	servicerConfDir = "servicer"
+	// 错误信息本地化配置的目录, 相对于conf目录, 见 errs.LoadMessagesDir
+	errsConfDir = "errs"
+)
+
+// runCheck 检查所有配置, 问题输出到w, 返回问题的个数
+func runCheck(f Flags, w io.Writer) int {
+	errs := checkConfig(f)
+	for _, err := range errs {
+		fmt.Fprintf(w, "[CHECK] %v\n", err)
+	}
+	if len(errs) > 0 {
+		fmt.Fprintf(w, "[CHECK] %d problem(s) found\n", len(errs))
+		return len(errs)
+	}
+	fmt.Fprintln(w, "[CHECK] ok")
+	return 0
+}
+
+// checkConfig 解析app.toml及servicer、errs目录下所有的配置, 返回发现的所有问题
+func checkConfig(f Flags) []error {
+	c, err := parseAppConfig(f.ConfPath, f.Sets)
+	if err != nil {
+		return []error{fmt.Errorf("%s: %w", f.ConfPath, err)}
+	}
+	// 配置中的 {env.xxx} 等依赖应用的环境信息
+	env.Default = c.Env
+
+	appConf := filepath.Join(c.Env.ConfDir(), filepath.Base(f.ConfPath))
+	var problems []error
+	for _, err := range checkAppConfig(c) {
+		problems = append(problems, fmt.Errorf("%s: %w", appConf, err))
+	}
+	problems = append(problems, checkServicerConfigs(c.Env.ConfDir())...)
+	// 加载到新的实例, 不影响默认实例
+	if err := errs.LoadMessagesDir(errs.New(), filepath.Join(c.Env.ConfDir(), errsConfDir)); err != nil {
+		problems = append(problems, err)
+	}
+	return problems
+}
+
+// checkAppConfig 检查app.toml中的各项配置是否可用
+func checkAppConfig(c *Config) []error {
+	var errs []error
+	switch c.RunMode {
+	case env.RunModeDebug, env.RunModeTest, env.RunModeRelease:
+	default:
+		errs = append(errs, fmt.Errorf("RunMode %q should be one of debug, test, release", c.RunMode))
+	}
+
+	app := NewApp(context.Background(), c, nil)
+	defer app.close()
+	if err := app.checkListeners(); err != nil {
+		errs = append(errs, err)
+	}
+
+	mc := c.HTTPServer.Middlewares
+	seen := make(map[string]bool, len(mc.Enabled))
+	middlewaresMu.RLock()
+	for _, name := range mc.Enabled {
+		if _, has := middlewares[name]; !has {
+			errs = append(errs, fmt.Errorf("middleware %q not registered", name))
+		}
+		if seen[name] {
+			errs = append(errs, fmt.Errorf("middleware %q enabled more than once", name))
+		}
+		seen[name] = true
+	}
+	middlewaresMu.RUnlock()
+	if seen["access_log"] {
+		if _, err := middleware.AccessLog(io.Discard, mc.AccessLog.Format); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	errs = append(errs, checkModules(c)...)
+
+	if dc := c.HTTPServer.Debug; dc.Enabled && dc.Token == "" && !c.hasAdminListener() && c.RunMode != env.RunModeRelease {
+		errs = append(errs, fmt.Errorf("HTTPServer.Debug.Token is required when debug routes are on the main listener"))
+	}
+
+	tc := c.HTTPServer.TLS
+	if _, has := clientAuthTypes[tc.ClientAuth]; tc.ClientAuth != "" && !has {
+		errs = append(errs, fmt.Errorf("ClientAuth %q not supported", tc.ClientAuth))
+	}
+	if tc.ClientCAFile != "" {
+		if _, err := os.Stat(confRelPath(c, tc.ClientCAFile, "")); err != nil {
+			errs = append(errs, fmt.Errorf("ClientCAFile: %w", err))
+		}
+	}
+	// 证书目录存在时检查证书能否加载
+	if certsDir := confRelPath(c, tc.CertsDir, appConfCertsDir); hasCertFile(certsDir) {
+		if _, err := NewCertProvider(certsDir); err != nil {
+			errs = append(errs, fmt.Errorf("certificates: %w", err))
+		}
+	}
+	return errs
+}
+
+// checkServicerConfigs 解析servicer目录下所有的配置文件
+func checkServicerConfigs(confDir string) []error {
+	files, err := conf.Glob(filepath.Join(confDir, servicerConfDir))
+	if err != nil {
+		return []error{err}
+	}
+	var errs []error
+	for _, file := range files {
+		// 同名的只会使用 conf.FileExts 中靠前的后缀
+		abs, _ := filepath.Abs(file)
+		name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		if found, err := conf.FindFile(filepath.Dir(file), name); err == nil && found != abs {
+			errs = append(errs, fmt.Errorf("%s: ignored, %s is used", file, filepath.Base(found)))
+			continue
+		}
+		var data map[string]interface{}
+		if err := conf.Parse(file, &data); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file, err))
+			continue
+		}
+		// 解析到对应的配置结构体, 执行其中的 default、validate tag
+		if err := conf.Parse(file, newServicerConfig(data)); err != nil {
+			errs = append(errs, splitFieldErrors(file, err)...)
+		}
+	}
+	return errs
+}
+
+// servicerConfigTypes 下游服务配置中特有的段及对应的配置结构体
+var servicerConfigTypes = []struct {
+	section string
+	new     func() interface{}
+}{
+	{"MySQL", func() interface{} { return &mysql.Config{} }},
+	{"Redis", func() interface{} { return &redis.Config{} }},
+	{"OSS", func() interface{} { return &oss.Config{} }},
+}
+
+// servicerConfig 没有特有的段时只检查公共的字段
+type servicerConfig struct {
+	Name string `validate:"required"`
+}
+
+// newServicerConfig 按配置中的段选择解析的结构体, 同解析到结构体时, key不区分大小写
+func newServicerConfig(data map[string]interface{}) interface{} {
+	for _, t := range servicerConfigTypes {
+		for k := range data {
+			if strings.EqualFold(k, t.section) {
+				return t.new()
+			}
+		}
+	}
+	return &servicerConfig{}
+}
+
+// splitFieldErrors 将 errors.Join 合并的字段错误拆开, 每个问题单独输出一行
+// 字段错误中已经有文件名, 其他错误加上文件名
+func splitFieldErrors(file string, err error) []error {
+	var fe *conf.FieldError
+	if !errors.As(err, &fe) {
+		return []error{fmt.Errorf("%s: %w", file, err)}
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+// checkModules 检查模块的依赖及 [Modules.<Name>] 配置
+func checkModules(c *Config) []error {
+	ordered, err := sortedModules()
+	if err != nil {
+		return []error{err}
+	}
+	var errs []error
+	registered := make(map[string]bool, len(ordered))
+	for _, m := range ordered {
+		registered[m.Name] = true
+		// 解析到新的对象, 不修改模块的配置
+		if m.Config != nil {
+			m1 := *m
+			m1.Config = reflect.New(reflect.TypeOf(m.Config).Elem()).Interface()
+			if err := m1.parseConfig(c); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	names := make([]string, 0, len(c.Modules))
+	for name := range c.Modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !registered[name] {
+			errs = append(errs, fmt.Errorf("[Modules.%s] module not registered", name))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,77 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-19 11:20:05
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-19 11:20:05
+ * @Description: -check 测试
+ */
+package bootstrap
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckServicerConfigs(t *testing.T) {
+	confDir := t.TempDir()
+	files := map[string]string{
+		"db_user.toml":  "Name = \"db_user\"\n[MySQL]\nUsername = \"root\"\n",
+		"db_redis.toml": "Name = \"db_redis\"\n[Resource.Manual]\nHost = \"127.0.0.1\"\nPort = 6379\n[Redis]\nDB = 1\n",
+		"oss_img.yaml":  "name: oss_img\noss:\n  accesskeyid: abc\n",
+		"cache.json":    `{"ExpireTime": 60}`,
+	}
+	if err := os.MkdirAll(filepath.Join(confDir, servicerConfDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(confDir, servicerConfDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []string
+	for _, err := range checkServicerConfigs(confDir) {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		"cache.json: Name: is required",
+		"db_user.toml: Resource.Manual.Host: is required",
+		"db_user.toml: Resource.Manual.Port: should be min=1",
+		"oss_img.yaml: OSS.Endpoint: is required",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d problems: %q", len(got), got)
+	}
+	for i, w := range want {
+		if !strings.Contains(got[i], w) {
+			t.Errorf("problem %d: got %q, want %q", i, got[i], w)
+		}
+	}
+}
+
+func TestFlagsNotOnCommandLine(t *testing.T) {
+	// 应用可以在 flag.CommandLine 上定义同名的参数
+	if flag.CommandLine.Lookup("conf") != nil {
+		t.Fatal("-conf registered on flag.CommandLine")
+	}
+	// 没有 Register 时不解析, 如 go test 的 -test.* 参数
+	var f0 Flags
+	if err := f0.parse([]string{"-test.v", "-runmode", "test"}); err != nil {
+		t.Fatal(err)
+	}
+	if f0.RunMode != "" {
+		t.Fatalf("parsed without Register: %+v", f0)
+	}
+
+	var f Flags
+	f.Register(flag.NewFlagSet("app", flag.ContinueOnError))
+	if err := f.parse([]string{"-runmode", "test", "-set", "APPName=a", "-set", "APPName=b"}); err != nil {
+		t.Fatal(err)
+	}
+	if f.RunMode != "test" || len(f.Sets) != 2 {
+		t.Fatalf("parsed: %+v", f)
+	}
+}
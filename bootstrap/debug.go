@@ -0,0 +1,146 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 17:31:14
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 17:31:14
+ * @Description: pprof及运行时信息等调试接口
+ */
+package bootstrap
+
+import (
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liziwei01/simple-boot/library/env"
+	"github.com/liziwei01/simple-boot/library/errs"
+	"github.com/liziwei01/simple-boot/library/resp"
+)
+
+const (
+	// 调试接口的路由前缀, net/http/pprof 只支持该前缀
+	debugPathPrefix = "/debug"
+	// 调试接口的token header, 不支持query参数, 避免token出现在访问日志中
+	debugTokenHeader = "X-Debug-Token"
+)
+
+// DebugConfig 调试接口配置
+//
+//	[HTTPServer.Debug]
+//	Enabled = true
+//	Token = "xxx"
+type DebugConfig struct {
+	// Enabled 是否启用 /debug 下的调试接口
+	// 配置了admin监听时注册在admin监听上, 否则仅在 RunMode 为 debug、test 时注册在业务监听上
+	Enabled bool
+	// Token 访问调试接口需要的token, 使用 X-Debug-Token header 传递
+	// 为空时不校验, 仅允许在admin监听上为空, 注册在业务监听上时必须配置
+	Token string
+}
+
+// registerDebugRoutes 按配置注册调试接口
+func registerDebugRoutes(app *AppServer) {
+	dc := app.Config.HTTPServer.Debug
+	if !dc.Enabled {
+		return
+	}
+	handler := internalHandler(app, "debug")
+	if handler == nil {
+		return
+	}
+	if handler == app.Handler && dc.Token == "" {
+		log.Println("[debug] debug routes are disabled on the main listener without HTTPServer.Debug.Token")
+		return
+	}
+
+	group := handler.Group(debugPathPrefix, debugAuth(dc.Token))
+	group.GET("/pprof/", gin.WrapF(pprof.Index))
+	group.GET("/pprof/cmdline", gin.WrapF(pprof.Cmdline))
+	group.GET("/pprof/profile", gin.WrapF(pprof.Profile))
+	group.GET("/pprof/symbol", gin.WrapF(pprof.Symbol))
+	group.POST("/pprof/symbol", gin.WrapF(pprof.Symbol))
+	group.GET("/pprof/trace", gin.WrapF(pprof.Trace))
+	// heap、goroutine、allocs 等profile
+	group.GET("/pprof/:name", gin.WrapF(pprof.Index))
+
+	group.GET("/goroutines", debugGoroutines)
+	group.GET("/memstats", debugMemStats)
+	group.GET("/buildinfo", debugBuildInfo)
+	group.GET("/env", func(c *gin.Context) {
+		c.JSON(http.StatusOK, app.Config.Env.Options())
+	})
+}
+
+// internalHandler 内部接口使用的handler, 配置了admin监听时为 AdminHandler,
+// 否则仅在 RunMode 为 debug、test 时使用 Handler, release 时返回nil
+func internalHandler(app *AppServer, name string) *gin.Engine {
+	if app.AdminHandler != nil {
+		return app.AdminHandler
+	}
+	runMode := app.Config.Env.RunMode()
+	if runMode != env.RunModeDebug && runMode != env.RunModeTest {
+		log.Printf("[%s] %s routes are disabled in run mode %q without an admin listener\n", name, name, runMode)
+		return nil
+	}
+	return app.Handler
+}
+
+// debugAuth 校验token, 为空时不校验
+func debugAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Next()
+			return
+		}
+		got := c.GetHeader(debugTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			resp.Error(c, errs.ErrUnauthorized.WithMessage("invalid debug token"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// debugGoroutines 所有goroutine的堆栈
+func debugGoroutines(c *gin.Context) {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", buf)
+}
+
+func debugMemStats(c *gin.Context) {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	c.JSON(http.StatusOK, gin.H{
+		"num_goroutine": runtime.NumGoroutine(),
+		"num_cpu":       runtime.NumCPU(),
+		"gomaxprocs":    runtime.GOMAXPROCS(0),
+		"memstats":      ms,
+	})
+}
+
+func debugBuildInfo(c *gin.Context) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		resp.Error(c, errs.ErrNotFound.WithMessage("build info not available"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"go_version": info.GoVersion,
+		"path":       info.Path,
+		"main":       info.Main,
+		"deps":       info.Deps,
+		"settings":   info.Settings,
+	})
+}
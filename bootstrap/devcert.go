@@ -0,0 +1,143 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 15:03:52
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 15:03:52
+ * @Description: debug模式下自动生成自签名证书, 方便本地测试https
+ */
+package bootstrap
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/liziwei01/simple-boot/library/env"
+)
+
+const (
+	// 自签名证书在 data 目录下的存放目录
+	devCertsDir = "certs"
+	// 自签名证书的有效期
+	devCertValidity = 365 * 24 * time.Hour
+	// 剩余有效期不足时重新生成
+	devCertRenewBefore = 24 * time.Hour
+)
+
+// ensureDevCert 确保dir下有可用的自签名证书
+// 已有的证书未过期且包含了当前所有的host时直接复用, 否则重新生成
+func ensureDevCert(dir string) error {
+	certFile := filepath.Join(dir, CrtFileName)
+	keyFile := filepath.Join(dir, KeyFileName)
+	hosts := devCertHosts()
+	if devCertUsable(certFile, keyFile, hosts) {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return generateSelfSignedCert(certFile, keyFile, hosts)
+}
+
+// devCertHosts 证书需要包含的域名及IP: localhost、主机名、本机IP
+func devCertHosts() []string {
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		hosts = append(hosts, hostname)
+	}
+	if ip := env.LocalIP(); ip != "unknown" && ip != "" {
+		hosts = append(hosts, ip)
+	}
+	return hosts
+}
+
+// devCertUsable 证书存在、未过期且包含所有的hosts
+func devCertUsable(certFile string, keyFile string, hosts []string) bool {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return false
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return false
+	}
+	if time.Now().Add(devCertRenewBefore).After(leaf.NotAfter) {
+		return false
+	}
+	for _, host := range hosts {
+		if leaf.VerifyHostname(host) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// generateSelfSignedCert 生成ECDSA P-256的自签名证书, 有效期为 devCertValidity
+func generateSelfSignedCert(certFile string, keyFile string, hosts []string) error {
+	return generateCert(certFile, keyFile, hosts, time.Now().Add(devCertValidity))
+}
+
+// generateCert 生成在notAfter过期的自签名证书
+func generateCert(certFile string, keyFile string, hosts []string, notAfter time.Time) error {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{env.AppName() + " development"},
+			CommonName:   "localhost",
+		},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, host)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &priv.PublicKey, priv)
+	if err != nil {
+		return fmt.Errorf("create certificate: %w", err)
+	}
+	keyDer, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return err
+	}
+	// 先写key再写证书, 证书存在即表示生成完成
+	if err := writePEM(keyFile, "PRIVATE KEY", keyDer, 0600); err != nil {
+		return err
+	}
+	return writePEM(certFile, "CERTIFICATE", der, 0644)
+}
+
+func writePEM(fileName string, typ string, der []byte, perm os.FileMode) error {
+	content := pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+	tmpFile := fileName + ".tmp"
+	if err := os.WriteFile(tmpFile, content, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, fileName)
+}
@@ -0,0 +1,70 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-19 16:40:18
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-19 16:40:18
+ * @Description: 自签名证书测试
+ */
+package bootstrap
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEnsureDevCert(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), devCertsDir)
+	certFile, keyFile := filepath.Join(dir, CrtFileName), filepath.Join(dir, KeyFileName)
+	readCert := func() []byte {
+		t.Helper()
+		content, err := os.ReadFile(certFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return content
+	}
+	ensure := func() {
+		t.Helper()
+		if err := ensureDevCert(dir); err != nil {
+			t.Fatal(err)
+		}
+		if !devCertUsable(certFile, keyFile, devCertHosts()) {
+			t.Fatal("generated cert not usable")
+		}
+	}
+
+	// 第一次生成, 包含所有的host
+	ensure()
+	first := readCert()
+
+	// 有效的证书直接复用
+	ensure()
+	if !bytes.Equal(readCert(), first) {
+		t.Fatal("valid cert regenerated")
+	}
+
+	// host变化后重新生成
+	if err := generateSelfSignedCert(certFile, keyFile, []string{"localhost"}); err != nil {
+		t.Fatal(err)
+	}
+	stale := readCert()
+	ensure()
+	if bytes.Equal(readCert(), stale) {
+		t.Fatal("cert missing hosts not regenerated")
+	}
+
+	// 已过期及即将过期的重新生成
+	for _, notAfter := range []time.Time{time.Now().Add(-time.Minute), time.Now().Add(devCertRenewBefore / 2)} {
+		if err := generateCert(certFile, keyFile, devCertHosts(), notAfter); err != nil {
+			t.Fatal(err)
+		}
+		stale = readCert()
+		ensure()
+		if bytes.Equal(readCert(), stale) {
+			t.Fatalf("cert expiring at %v not regenerated", notAfter)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 17:52:36
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 17:52:36
+ * @Description: 命令行参数及环境变量
+ */
+package bootstrap
+
+import (
+	"flag"
+	"os"
+	"strings"
+)
+
+const (
+	// 与命令行参数对应的环境变量, 命令行参数优先
+	envConfPath = "SIMPLE_BOOT_CONF"
+	envRunMode  = "SIMPLE_BOOT_RUNMODE"
+	// 多个用 ; 分隔, 如 HTTPServer.Listen=:9000;RunMode=test
+	envSet = "SIMPLE_BOOT_SET"
+)
+
+// Flags bootstrap支持的命令行参数
+//
+//	-conf ./conf/app.toml
+//	-set HTTPServer.Listen=:9000 -set HTTPServer.ShutdownTimeout=3000
+//	-runmode debug
+//	-check
+//	-openapi ./api/openapi.yaml
+type Flags struct {
+	// ConfPath app.toml的路径, 默认为 ./conf/app.toml
+	ConfPath string
+	// RunMode 覆盖app.toml中的RunMode
+	RunMode string
+	// Sets 覆盖app.toml中的配置, 格式为 字段路径=值
+	Sets []string
+	// Check 只检查配置文件, 打印问题, Setup 不再继续准备, 见 ErrChecked
+	Check bool
+	// OpenAPI 将由路由表生成的 OpenAPI 文档写入该文件后退出, 不启动服务, - 为标准输出
+	OpenAPI string
+
+	// fs 调用 Register 时注册到的FlagSet
+	fs *flag.FlagSet
+}
+
+// CommandLine Setup 使用的参数
+// 需要应用在 flag.Parse 前调用 CommandLine.Register(flag.CommandLine) 才会读取命令行参数,
+// 否则 Setup 只读取环境变量, 不解析 os.Args, 不影响应用自己的参数及 go test 的 -test.* 参数
+var CommandLine = &Flags{}
+
+// Register 将参数注册到fs上, 之后 Setup 使用fs的解析结果, fs还没有解析时使用 os.Args[1:] 解析
+func (f *Flags) Register(fs *flag.FlagSet) {
+	f.fs = fs
+	fs.StringVar(&f.ConfPath, "conf", "", "path of app.toml, default "+appConfPath+", env "+envConfPath)
+	fs.StringVar(&f.RunMode, "runmode", "", "override RunMode in app.toml: debug, test or release, env "+envRunMode)
+	fs.Var((*setFlag)(&f.Sets), "set", "override a value in app.toml, e.g. HTTPServer.Listen=:9000, can be repeated, env "+envSet)
+	fs.BoolVar(&f.Check, "check", false, "check app.toml, conf/servicer/* and conf/errs/*, print problems and exit")
+	fs.StringVar(&f.OpenAPI, "openapi", "", "write the OpenAPI document of registered routes to the file (.json, .yaml or - for stdout) and exit instead of serving")
+}
+
+// parse 解析命令行参数, 没有调用过 Register 时不解析
+func (f *Flags) parse(args []string) error {
+	if f.fs == nil || f.fs.Parsed() {
+		return nil
+	}
+	return f.fs.Parse(args)
+}
+
+// withEnv 命令行参数为空时使用环境变量
+func (f Flags) withEnv() Flags {
+	if f.ConfPath == "" {
+		f.ConfPath = os.Getenv(envConfPath)
+	}
+	if f.ConfPath == "" {
+		f.ConfPath = appConfPath
+	}
+	if f.RunMode == "" {
+		f.RunMode = os.Getenv(envRunMode)
+	}
+	if sets := os.Getenv(envSet); sets != "" {
+		var envSets []string
+		for _, set := range strings.Split(sets, ";") {
+			if set = strings.TrimSpace(set); set != "" {
+				envSets = append(envSets, set)
+			}
+		}
+		// 命令行的在后面, 优先级更高
+		f.Sets = append(envSets, f.Sets...)
+	}
+	if f.RunMode != "" {
+		f.Sets = append(f.Sets, "RunMode="+f.RunMode)
+	}
+	return f
+}
+
+// setFlag 可以重复的 -set 参数
+type setFlag []string
+
+func (s *setFlag) String() string {
+	if s == nil {
+		return ""
+	}
+	return strings.Join(*s, ";")
+}
+
+func (s *setFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
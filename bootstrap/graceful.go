@@ -0,0 +1,140 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 10:12:40
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 10:12:40
+ * @Description: 优雅退出
+ */
+package bootstrap
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	// 默认的优雅退出等待时间
+	defaultShutdownTimeout = 10 * time.Second
+)
+
+// ShutdownSignals 触发优雅退出的信号
+var ShutdownSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+
+// serve 在后台运行所有的http服务, 并等待退出信号
+// 收到信号、app.ctx被取消或任意一个服务异常退出时, 所有服务停止接收新连接,
+// 在ShutdownTimeout内等待已有请求处理完成, 返回第一个异常退出的错误
+func (app *App) serve(tlsConfig *tls.Config) error {
+	defer app.close()
+
+	errCh := make(chan error, len(app.servers))
+	for _, s := range app.servers {
+		s := s
+		fmt.Fprintf(DefaultWriter, "[APP START] Listening and serving %s on %s\n", s.scheme(), s.address())
+		go func() {
+			errCh <- s.serve(tlsConfig)
+		}()
+	}
+	running := len(app.servers)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, ShutdownSignals...)
+	defer signal.Stop(sigCh)
+
+	restartCh := make(chan os.Signal, 1)
+	if len(RestartSignals) > 0 {
+		signal.Notify(restartCh, RestartSignals...)
+		defer signal.Stop(restartCh)
+	}
+
+	// 已经开始监听, 通知父进程(若有)可以退出了
+	notifyReady()
+
+	var errServe error
+wait:
+	for {
+		select {
+		case err := <-errCh:
+			// 服务自己退出了, 比如证书错误, 其他服务也一起退出
+			running--
+			errServe = ignoreServerClosed(err)
+			fmt.Fprintf(DefaultWriter, "[APP SHUTDOWN] Server exit: %v, shutting down\n", errServe)
+			break wait
+		case sig := <-sigCh:
+			fmt.Fprintf(DefaultWriter, "[APP SHUTDOWN] Receive signal %s, shutting down\n", sig)
+			break wait
+		case sig := <-restartCh:
+			fmt.Fprintf(DefaultWriter, "[APP RESTART] Receive signal %s, starting new process\n", sig)
+			if err := app.restart(); err != nil {
+				// 新进程没有起来, 老进程继续提供服务
+				fmt.Fprintf(DefaultWriter, "[APP RESTART] Restart failed, keep serving: %v\n", err)
+				continue
+			}
+			fmt.Fprintf(DefaultWriter, "[APP RESTART] New process is ready, shutting down\n")
+			break wait
+		case <-app.ctx.Done():
+			fmt.Fprintf(DefaultWriter, "[APP SHUTDOWN] Context done, shutting down\n")
+			break wait
+		}
+	}
+
+	errShutdown := app.Shutdown()
+	for ; running > 0; running-- {
+		if err := ignoreServerClosed(<-errCh); err != nil && errServe == nil {
+			errServe = err
+		}
+	}
+	if errServe != nil {
+		return errServe
+	}
+	return errShutdown
+}
+
+// Shutdown 优雅关闭所有的http服务
+// 超过ShutdownTimeout仍未处理完的连接将被强制关闭
+func (app *App) Shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), app.shutdownTimeout())
+	defer cancel()
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, s := range app.servers {
+		s := s
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.shutdown(ctx); err != nil {
+				_ = s.Close()
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("shutdown %q: %w", s.Name, err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func (app *App) shutdownTimeout() time.Duration {
+	if app.config.HTTPServer.ShutdownTimeout > 0 {
+		return time.Millisecond * time.Duration(app.config.HTTPServer.ShutdownTimeout)
+	}
+	return defaultShutdownTimeout
+}
+
+// Shutdown 之后 Serve 会返回 http.ErrServerClosed, 属于正常退出
+func ignoreServerClosed(err error) error {
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
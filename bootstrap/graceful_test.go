@@ -0,0 +1,114 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-19 15:20:36
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-19 15:20:36
+ * @Description: 优雅退出测试
+ */
+package bootstrap
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// freeAddr 取一个空闲的本地端口
+func freeAddr(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	return ln.Addr().String()
+}
+
+func TestGracefulShutdown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := gin.New()
+	handler.GET("/slow", func(c *gin.Context) {
+		close(started)
+		<-release
+		c.String(http.StatusOK, "done")
+	})
+
+	addr := freeAddr(t)
+	c := &Config{}
+	c.HTTPServer.Listen = addr
+	c.HTTPServer.ShutdownTimeout = 5000
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	app := NewApp(ctx, c, handler)
+	served := make(chan error, 1)
+	go func() {
+		served <- app.Start()
+	}()
+
+	// 等待开始监听
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("server not listening: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	type result struct {
+		body string
+		err  error
+	}
+	inflight := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr + "/slow")
+		if err != nil {
+			inflight <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		inflight <- result{body: string(body), err: err}
+	}()
+	<-started
+
+	cancel()
+	// 退出开始后不再接收新连接
+	deadline = time.Now().Add(5 * time.Second)
+	for {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			break
+		}
+		conn.Close()
+		if time.Now().After(deadline) {
+			t.Fatal("new connections still accepted after shutdown started")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case err := <-served:
+		t.Fatalf("returned before the in-flight request finished: %v", err)
+	default:
+	}
+
+	// 已有的请求正常处理完成
+	close(release)
+	r := <-inflight
+	if r.err != nil || r.body != "done" {
+		t.Fatalf("in-flight request: %q %v", r.body, r.err)
+	}
+	if err := <-served; err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+}
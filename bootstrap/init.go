@@ -8,13 +8,59 @@
 package bootstrap
 
 import (
+	"log"
+
 	"github.com/gin-gonic/gin"
+	"github.com/liziwei01/simple-boot/library/health"
+	"github.com/liziwei01/simple-boot/library/metrics"
+)
+
+const (
+	// 存活检查路由
+	livenessPath = "/healthz"
+	// 就绪检查路由
+	readinessPath = "/readyz"
+	// prometheus指标, 仅在admin监听上
+	metricsPath = "/metrics"
 )
 
 // InitHandler 用*gin.Engine作http handler
+// 按 [HTTPServer.Middlewares] 的配置依次使用中间件, 中间件创建失败时panic, 需要错误时使用 NewHandler
 func InitHandler(app *AppServer) *gin.Engine {
+	handler, err := NewHandler(app)
+	if err != nil {
+		log.Panicf("init handler: %v", err)
+	}
+	return handler
+}
+
+// NewHandler 同 InitHandler, 中间件创建失败时返回错误
+func NewHandler(app *AppServer) (*gin.Engine, error) {
 	gin.SetMode(app.Config.RunMode)
-	handler := gin.Default()
+	handlers, err := newMiddlewares(app)
+	if err != nil {
+		return nil, err
+	}
+	handler := gin.New()
+	handler.Use(handlers...)
 	handler.ContextWithFallback = true
+	registerHealthRoutes(handler)
+	return handler, nil
+}
+
+// InitAdminHandler 内部管理端口使用独立的*gin.Engine, 与业务路由隔离
+// 注册健康检查及 /metrics, 指标见 metrics.Gatherer
+func InitAdminHandler(app *AppServer) *gin.Engine {
+	handler := gin.New()
+	handler.Use(gin.Recovery())
+	handler.ContextWithFallback = true
+	registerHealthRoutes(handler)
+	handler.GET(metricsPath, metrics.HandlerFor(metrics.Gatherer))
 	return handler
 }
+
+// registerHealthRoutes 注册存活及就绪检查, 检查项见 health.Default
+func registerHealthRoutes(handler *gin.Engine) {
+	handler.GET(livenessPath, health.LivenessHandler(health.Default))
+	handler.GET(readinessPath, health.ReadinessHandler(health.Default))
+}
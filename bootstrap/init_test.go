@@ -0,0 +1,80 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-19 12:10:18
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-19 12:10:18
+ * @Description: handler初始化测试
+ */
+package bootstrap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liziwei01/simple-boot/library/env"
+	"github.com/liziwei01/simple-boot/library/lifecycle"
+	"github.com/liziwei01/simple-boot/library/metrics"
+)
+
+func TestAdminMetrics(t *testing.T) {
+	if err := metrics.RegisterConnState(metrics.Registerer); err != nil {
+		t.Fatal(err)
+	}
+	metrics.ConnectionsTotal.WithLabelValues("admin_test").Inc()
+
+	handler := InitAdminHandler(&AppServer{})
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, metricsPath, nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `simple_boot_http_server_connections_total{listener="admin_test"} 1`) {
+		t.Fatalf("admin /metrics: %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSetupFailureAccessLog(t *testing.T) {
+	lifecycleBefore, envBefore, ginMode := lifecycle.Default, env.Default, gin.Mode()
+	t.Cleanup(func() {
+		lifecycle.Default, env.Default = lifecycleBefore, envBefore
+		gin.SetMode(ginMode)
+	})
+	lifecycle.Default = lifecycle.New()
+
+	confPath := filepath.Join(t.TempDir(), "conf", "app.toml")
+	if err := os.MkdirAll(filepath.Dir(confPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeConf := func(enabled string) {
+		t.Helper()
+		content := "APPName = \"accesslog\"\nRunMode = \"test\"\n[HTTPServer.Middlewares]\nEnabled = " + enabled + "\n"
+		if err := os.WriteFile(confPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// access_log 创建后, 后面的中间件失败
+	writeConf(`["access_log", "no_such_middleware"]`)
+	if _, err := SetupWithFlags(Flags{ConfPath: confPath}); err == nil {
+		t.Fatal("unknown middleware should fail")
+	}
+	if names, _ := lifecycle.Default.Names(); len(names) != 0 {
+		t.Fatalf("hooks left by failed setup: %v", names)
+	}
+
+	// 再次 Setup 不会因为同名的hook失败
+	writeConf(`["access_log"]`)
+	app, err := SetupWithFlags(Flags{ConfPath: confPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names, _ := app.Lifecycle.Names(); len(names) != 1 || names[0] != "access_log" {
+		t.Fatalf("hooks: %v", names)
+	}
+	if err := app.Lifecycle.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
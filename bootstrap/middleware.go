@@ -0,0 +1,152 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 16:12:40
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 16:12:40
+ * @Description: 中间件注册, 按app.toml中声明的顺序启用
+ */
+package bootstrap
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liziwei01/simple-boot/library/middleware"
+)
+
+// MiddlewareFactory 根据app的配置创建中间件
+type MiddlewareFactory func(app *AppServer) (gin.HandlerFunc, error)
+
+// MiddlewaresConfig 中间件配置
+//
+//	[HTTPServer.Middlewares]
+//	Enabled = ["request_id", "access_log", "recovery", "cors"]
+//	[HTTPServer.Middlewares.CORS]
+//	AllowOrigins = ["https://*.example.com"]
+type MiddlewaresConfig struct {
+	// Enabled 启用的中间件, 按声明的顺序执行, 默认为 ["logger", "recovery"]
+	Enabled []string
+
+	AccessLog       AccessLogConfig
+	CORS            middleware.CORSConfig
+	SecurityHeaders middleware.SecurityHeadersConfig
+	Gzip            middleware.GzipConfig
+	RateLimit       middleware.RateLimitConfig
+	// BodyLimit 请求体的最大字节数, 默认4MB
+	BodyLimit int64
+	// Timeout 单个请求的超时时间, 默认10s
+	Timeout int // ms
+}
+
+const (
+	defaultBodyLimit      = 4 << 20
+	defaultRequestTimeout = 10 * time.Second
+)
+
+// defaultMiddlewares 与 gin.Default() 一致
+var defaultMiddlewares = []string{"logger", "recovery"}
+
+var (
+	middlewaresMu sync.RWMutex
+	middlewares   = map[string]MiddlewareFactory{}
+)
+
+func init() {
+	builtins := map[string]MiddlewareFactory{
+		"logger": func(app *AppServer) (gin.HandlerFunc, error) {
+			return gin.Logger(), nil
+		},
+		"request_id": func(app *AppServer) (gin.HandlerFunc, error) {
+			return middleware.RequestID(), nil
+		},
+		"access_log": func(app *AppServer) (gin.HandlerFunc, error) {
+			w, err := newAccessLogWriter(app)
+			if err != nil {
+				return nil, err
+			}
+			return middleware.AccessLog(w, app.Config.HTTPServer.Middlewares.AccessLog.Format)
+		},
+		"recovery": func(app *AppServer) (gin.HandlerFunc, error) {
+			return middleware.Recovery(gin.DefaultErrorWriter), nil
+		},
+		"cors": func(app *AppServer) (gin.HandlerFunc, error) {
+			return middleware.CORS(app.Config.HTTPServer.Middlewares.CORS), nil
+		},
+		"security_headers": func(app *AppServer) (gin.HandlerFunc, error) {
+			return middleware.SecurityHeaders(app.Config.HTTPServer.Middlewares.SecurityHeaders), nil
+		},
+		"gzip": func(app *AppServer) (gin.HandlerFunc, error) {
+			return middleware.Gzip(app.Config.HTTPServer.Middlewares.Gzip)
+		},
+		"rate_limit": func(app *AppServer) (gin.HandlerFunc, error) {
+			return middleware.RateLimit(app.Config.HTTPServer.Middlewares.RateLimit)
+		},
+		"body_limit": func(app *AppServer) (gin.HandlerFunc, error) {
+			limit := app.Config.HTTPServer.Middlewares.BodyLimit
+			if limit <= 0 {
+				limit = defaultBodyLimit
+			}
+			return middleware.BodyLimit(limit), nil
+		},
+		"timeout": func(app *AppServer) (gin.HandlerFunc, error) {
+			timeout := defaultRequestTimeout
+			if ms := app.Config.HTTPServer.Middlewares.Timeout; ms > 0 {
+				timeout = time.Millisecond * time.Duration(ms)
+			}
+			return middleware.Timeout(timeout), nil
+		},
+	}
+	for name, factory := range builtins {
+		if err := RegisterMiddleware(name, factory); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterMiddleware 注册一个命名的中间件, 在 [HTTPServer.Middlewares] Enabled 中启用
+// 需要在 Setup 之前注册, 如在init中
+func RegisterMiddleware(name string, factory MiddlewareFactory) error {
+	if name == "" {
+		return fmt.Errorf("middleware name is empty, not allow")
+	}
+	if factory == nil {
+		return fmt.Errorf("middleware=%q factory is nil", name)
+	}
+	middlewaresMu.Lock()
+	defer middlewaresMu.Unlock()
+	if _, has := middlewares[name]; has {
+		return fmt.Errorf("middleware=%q already registered", name)
+	}
+	middlewares[name] = factory
+	return nil
+}
+
+// newMiddlewares 按配置的顺序创建所有启用的中间件
+func newMiddlewares(app *AppServer) ([]gin.HandlerFunc, error) {
+	enabled := app.Config.HTTPServer.Middlewares.Enabled
+	if enabled == nil {
+		enabled = defaultMiddlewares
+	}
+	middlewaresMu.RLock()
+	defer middlewaresMu.RUnlock()
+	handlers := make([]gin.HandlerFunc, 0, len(enabled))
+	seen := make(map[string]bool, len(enabled))
+	for _, name := range enabled {
+		if seen[name] {
+			return nil, fmt.Errorf("middleware=%q enabled more than once", name)
+		}
+		seen[name] = true
+		factory, has := middlewares[name]
+		if !has {
+			return nil, fmt.Errorf("middleware=%q not registered", name)
+		}
+		handler, err := factory(app)
+		if err != nil {
+			return nil, fmt.Errorf("middleware=%q: %w", name, err)
+		}
+		handlers = append(handlers, handler)
+	}
+	return handlers, nil
+}
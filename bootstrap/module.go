@@ -0,0 +1,177 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 19:58:02
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 19:58:02
+ * @Description: 业务模块注册, Setup 时按依赖顺序解析配置、注册路由及生命周期回调
+ */
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/gin-gonic/gin"
+	"github.com/liziwei01/simple-boot/library/conf"
+	"github.com/liziwei01/simple-boot/library/lifecycle"
+	"github.com/liziwei01/simple-boot/library/router"
+)
+
+// Module 一个业务模块, 声明自己的配置、路由及启动退出回调
+//
+//	bootstrap.RegisterModule(bootstrap.Module{
+//		Name:      "user",
+//		DependsOn: []string{"mysql"},
+//		Config:    &userConf,
+//		Routes:    func(handler *gin.Engine) { handler.GET("/user/:id", getUser) },
+//	})
+//
+// 对应app.toml中的
+//
+//	[Modules.user]
+//	PageSize = 20
+type Module struct {
+	// Name 唯一的名字, 同时作为生命周期回调的名字, 必选
+	Name string
+	// DependsOn 依赖的其他模块或生命周期回调(如mysql), 被依赖的先初始化、先启动、后退出
+	DependsOn []string
+	// Config 解析 [Modules.<Name>] 的目标, 需要为指针, 为空时不解析
+	Config interface{}
+	// Routes 在业务路由上注册该模块的路由, 可为空
+	Routes func(handler *gin.Engine)
+	// Controllers 挂载到 AppServer.Router 的控制器, 可为空
+	Controllers []router.Controller
+	// Start 启动时执行, 可为空
+	Start func(ctx context.Context) error
+	// Stop 退出时执行, 可为空
+	Stop func(ctx context.Context) error
+	// Timeout Start、Stop各自的超时时间, 默认为 lifecycle.DefaultTimeout
+	Timeout time.Duration
+}
+
+var (
+	modulesMu sync.Mutex
+	modules   []*Module
+)
+
+// RegisterModule 注册一个模块, 需要在 Setup 之前注册, 如在init中
+func RegisterModule(m Module) error {
+	if m.Name == "" {
+		return fmt.Errorf("module name is empty, not allow")
+	}
+	if m.Config != nil && reflect.TypeOf(m.Config).Kind() != reflect.Pointer {
+		return fmt.Errorf("module=%q Config should be a pointer", m.Name)
+	}
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+	for _, m1 := range modules {
+		if m1.Name == m.Name {
+			return fmt.Errorf("module=%q already registered", m.Name)
+		}
+	}
+	modules = append(modules, &m)
+	return nil
+}
+
+// initModules 按依赖顺序解析各模块的配置, 注册路由及生命周期回调
+func initModules(app *AppServer) error {
+	ordered, err := sortedModules()
+	if err != nil {
+		return err
+	}
+	for _, m := range ordered {
+		if err := m.parseConfig(app.Config); err != nil {
+			return err
+		}
+		if m.Routes != nil {
+			m.Routes(app.Handler)
+		}
+		app.Router.Mount(m.Controllers...)
+		err := app.Lifecycle.Register(lifecycle.Hook{
+			Name:      m.Name,
+			DependsOn: m.DependsOn,
+			OnStart:   m.Start,
+			OnStop:    m.Stop,
+			Timeout:   m.Timeout,
+		})
+		if err != nil {
+			return fmt.Errorf("module=%q: %w", m.Name, err)
+		}
+	}
+	return nil
+}
+
+// sortedModules 按模块之间的依赖排序, 依赖的生命周期回调由 lifecycle 在启动时检查
+func sortedModules() ([]*Module, error) {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+	byName := make(map[string]*Module, len(modules))
+	for _, m := range modules {
+		byName[m.Name] = m
+	}
+	// 复用 lifecycle 的排序及循环依赖检查
+	sorter := lifecycle.New()
+	for _, m := range modules {
+		var deps []string
+		for _, dep := range m.DependsOn {
+			if _, has := byName[dep]; has {
+				deps = append(deps, dep)
+			}
+		}
+		if err := sorter.Register(lifecycle.Hook{Name: m.Name, DependsOn: deps}); err != nil {
+			return nil, err
+		}
+	}
+	names, err := sorter.Names()
+	if err != nil {
+		return nil, fmt.Errorf("modules: %w", err)
+	}
+	ordered := make([]*Module, 0, len(names))
+	for _, name := range names {
+		ordered = append(ordered, byName[name])
+	}
+	return ordered, nil
+}
+
+// parseConfig 将 [Modules.<Name>] 解析到 m.Config, 没有配置时保持原值
+func (m *Module) parseConfig(c *Config) error {
+	section, has := c.Modules[m.Name]
+	if !has || m.Config == nil {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(section); err != nil {
+		return fmt.Errorf("module=%q config: %w", m.Name, err)
+	}
+	if err := conf.ParseBytes(conf.FileTOML, buf.Bytes(), m.Config); err != nil {
+		return fmt.Errorf("module=%q config: %w", m.Name, err)
+	}
+	return nil
+}
+
+// SaveRegistries 保存已注册的模块及中间件, 返回的函数恢复到保存时的状态
+// 用于测试中注册的模块、中间件不影响其他测试, 见 bootstraptest
+func SaveRegistries() (restore func()) {
+	modulesMu.Lock()
+	savedModules := append([]*Module(nil), modules...)
+	modulesMu.Unlock()
+	middlewaresMu.RLock()
+	savedMiddlewares := make(map[string]MiddlewareFactory, len(middlewares))
+	for name, factory := range middlewares {
+		savedMiddlewares[name] = factory
+	}
+	middlewaresMu.RUnlock()
+	return func() {
+		modulesMu.Lock()
+		modules = savedModules
+		modulesMu.Unlock()
+		middlewaresMu.Lock()
+		middlewares = savedMiddlewares
+		middlewaresMu.Unlock()
+	}
+}
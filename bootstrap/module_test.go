@@ -0,0 +1,119 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 19:58:02
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 19:58:02
+ * @Description: 模块注册测试
+ */
+package bootstrap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liziwei01/simple-boot/library/env"
+	"github.com/liziwei01/simple-boot/library/lifecycle"
+)
+
+func TestModules(t *testing.T) {
+	modulesBefore, lifecycleBefore, envBefore, ginMode := modules, lifecycle.Default, env.Default, gin.Mode()
+	t.Cleanup(func() {
+		modules, lifecycle.Default, env.Default = modulesBefore, lifecycleBefore, envBefore
+		gin.SetMode(ginMode)
+	})
+	modules = nil
+	lifecycle.Default = lifecycle.New()
+
+	confPath := filepath.Join(t.TempDir(), "conf", "app.toml")
+	if err := os.MkdirAll(filepath.Dir(confPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := `
+APPName = "modules"
+RunMode = "test"
+[HTTPServer]
+Listen = "127.0.0.1:0"
+[Modules.user]
+PageSize = 50
+Tags = ["a", "b"]
+`
+	if err := os.WriteFile(confPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var started []string
+	userConf := struct {
+		PageSize int
+		Tags     []string
+	}{PageSize: 20}
+	mustRegister := func(m Module) {
+		t.Helper()
+		if err := RegisterModule(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// user 先注册, 但依赖 cache, 初始化及启动在 cache 之后
+	mustRegister(Module{
+		Name:      "user",
+		DependsOn: []string{"cache"},
+		Config:    &userConf,
+		Routes: func(handler *gin.Engine) {
+			handler.GET("/user", func(c *gin.Context) { c.JSON(http.StatusOK, userConf.PageSize) })
+		},
+		Start: func(ctx context.Context) error {
+			started = append(started, "user")
+			return nil
+		},
+	})
+	mustRegister(Module{
+		Name: "cache",
+		Start: func(ctx context.Context) error {
+			started = append(started, "cache")
+			return nil
+		},
+	})
+	if err := RegisterModule(Module{Name: "cache"}); err == nil {
+		t.Fatal("duplicate module should fail")
+	}
+	if err := RegisterModule(Module{Name: "bad", Config: userConf}); err == nil {
+		t.Fatal("non-pointer Config should fail")
+	}
+
+	app, err := SetupWithFlags(Flags{ConfPath: confPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if userConf.PageSize != 50 || !reflect.DeepEqual(userConf.Tags, []string{"a", "b"}) {
+		t.Fatalf("config not parsed: %+v", userConf)
+	}
+	w := httptest.NewRecorder()
+	app.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/user", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "50" {
+		t.Fatalf("route: %d %s", w.Code, w.Body)
+	}
+	if err := app.Lifecycle.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer app.Lifecycle.Stop(context.Background())
+	if !reflect.DeepEqual(started, []string{"cache", "user"}) {
+		t.Fatalf("start order: %v", started)
+	}
+
+	// 循环依赖及未注册模块的配置
+	mustRegister(Module{Name: "a", DependsOn: []string{"b"}})
+	mustRegister(Module{Name: "b", DependsOn: []string{"a"}})
+	if errs := checkModules(app.Config); len(errs) != 1 {
+		t.Fatalf("cycle errs=%v", errs)
+	}
+	modules = modules[:2]
+	app.Config.Modules["order"] = map[string]interface{}{}
+	if errs := checkModules(app.Config); len(errs) != 1 {
+		t.Fatalf("unregistered errs=%v", errs)
+	}
+}
@@ -0,0 +1,142 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 20:47:19
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 20:47:19
+ * @Description: 提供由路由表生成的 OpenAPI 文档
+ */
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liziwei01/simple-boot/library/errs"
+	"github.com/liziwei01/simple-boot/library/openapi"
+	"github.com/liziwei01/simple-boot/library/resp"
+)
+
+const (
+	openAPIJSONPath = "/openapi.json"
+	openAPIYAMLPath = "/openapi.yaml"
+	// 文档默认的版本
+	defaultOpenAPIVersion = "1.0.0"
+)
+
+// OpenAPIConfig 文档配置
+//
+//	[HTTPServer.OpenAPI]
+//	Enabled = true
+//	Title = "user api"
+//	Version = "1.2.0"
+type OpenAPIConfig struct {
+	// Enabled 是否提供 /openapi.json、/openapi.yaml, 位置同调试接口, 见 DebugConfig
+	Enabled bool
+	// Title 默认为APPName
+	Title string
+	// Version 默认为1.0.0
+	Version     string
+	Description string
+}
+
+// OpenAPI 根据 Router 中注册的路由生成文档
+func (appServer *AppServer) OpenAPI() *openapi.Document {
+	oc := appServer.Config.HTTPServer.OpenAPI
+	info := openapi.Info{
+		Title:       oc.Title,
+		Version:     oc.Version,
+		Description: oc.Description,
+	}
+	if info.Title == "" {
+		info.Title = appServer.Config.APPName
+	}
+	if info.Version == "" {
+		info.Version = defaultOpenAPIVersion
+	}
+	return openapi.Generate(info, appServer.Router.Routes())
+}
+
+// openAPIDoc 启动时生成的文档
+type openAPIDoc struct {
+	json []byte
+	yaml []byte
+}
+
+// registerOpenAPIRoutes 按配置注册文档路由, 文档在启动时生成, 此时所有路由已经注册
+func registerOpenAPIRoutes(app *AppServer) error {
+	if !app.Config.HTTPServer.OpenAPI.Enabled {
+		return nil
+	}
+	handler := internalHandler(app, "openapi")
+	if handler == nil {
+		return nil
+	}
+	var doc atomic.Pointer[openAPIDoc]
+	err := app.OnStart("openapi", func(ctx context.Context) error {
+		d := app.OpenAPI()
+		jsonData, err := d.JSON()
+		if err != nil {
+			return err
+		}
+		yamlData, err := d.YAML()
+		if err != nil {
+			return err
+		}
+		doc.Store(&openAPIDoc{json: jsonData, yaml: yamlData})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	serve := func(contentType string, data func(d *openAPIDoc) []byte) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			d := doc.Load()
+			if d == nil {
+				resp.Error(c, errs.ErrServiceUnavailable.WithMessage("openapi document not generated yet"))
+				return
+			}
+			c.Data(http.StatusOK, contentType, data(d))
+		}
+	}
+	handler.GET(openAPIJSONPath, serve("application/json; charset=utf-8", func(d *openAPIDoc) []byte { return d.json }))
+	handler.GET(openAPIYAMLPath, serve("application/yaml; charset=utf-8", func(d *openAPIDoc) []byte { return d.yaml }))
+	return nil
+}
+
+// writeOpenAPI 将文档写入文件, 后缀为 .yaml、.yml 时为yaml, 否则为json, - 为标准输出
+func (appServer *AppServer) writeOpenAPI(path string) error {
+	doc := appServer.OpenAPI()
+	var (
+		data []byte
+		err  error
+	)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		data, err = doc.YAML()
+	default:
+		data, err = doc.JSON()
+	}
+	if err != nil {
+		return fmt.Errorf("openapi: %w", err)
+	}
+	if !bytes.HasSuffix(data, []byte("\n")) {
+		data = append(data, '\n')
+	}
+	if path == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("openapi: %w", err)
+	}
+	log.Printf("[openapi] %d route(s) written to %s\n", len(appServer.Router.Routes()), path)
+	return nil
+}
@@ -0,0 +1,104 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 17:52:36
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 17:52:36
+ * @Description: 使用 字段路径=值 覆盖配置
+ */
+package bootstrap
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// applyOverrides 依次使用sets覆盖obj中的值, 后面的优先
+func applyOverrides(obj interface{}, sets []string) error {
+	for _, set := range sets {
+		path, value, found := strings.Cut(set, "=")
+		if !found || strings.TrimSpace(path) == "" {
+			return fmt.Errorf("override %q should be like Field.SubField=value", set)
+		}
+		if err := setField(reflect.ValueOf(obj), strings.TrimSpace(path), value); err != nil {
+			return fmt.Errorf("override %q: %w", set, err)
+		}
+	}
+	return nil
+}
+
+// setField 按 . 分隔的路径找到字段并赋值, 字段名不区分大小写
+func setField(v reflect.Value, path string, value string) error {
+	for _, name := range strings.Split(path, ".") {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return fmt.Errorf("%q is not a struct", name)
+		}
+		field := v.FieldByNameFunc(func(fieldName string) bool {
+			return strings.EqualFold(fieldName, name)
+		})
+		if !field.IsValid() || !field.CanSet() {
+			return fmt.Errorf("field %q not found", name)
+		}
+		v = field
+	}
+	return setValue(v, value)
+}
+
+// setValue 将字符串转换为字段的类型, 切片使用 , 分隔
+func setValue(v reflect.Value, value string) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setValue(v.Elem(), value)
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		var items []string
+		if value != "" {
+			items = strings.Split(value, ",")
+		}
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setValue(slice.Index(i), strings.TrimSpace(item)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	default:
+		return fmt.Errorf("type %s not supported", v.Type())
+	}
+	return nil
+}
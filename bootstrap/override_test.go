@@ -0,0 +1,40 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 17:52:36
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 17:52:36
+ * @Description: 配置覆盖测试
+ */
+package bootstrap
+
+import (
+	"testing"
+)
+
+func TestApplyOverrides(t *testing.T) {
+	c := &Config{}
+	err := applyOverrides(c, []string{
+		"HTTPServer.Listen=:9000",
+		"httpserver.shutdowntimeout=3000",
+		"HTTPServer.Middlewares.Enabled=request_id, recovery",
+		"HTTPServer.Debug.Enabled=true",
+		"RunMode=test",
+		"RunMode=debug",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hs := c.HTTPServer
+	if hs.Listen != ":9000" || hs.ShutdownTimeout != 3000 || !hs.Debug.Enabled || c.RunMode != "debug" {
+		t.Fatalf("config=%+v", c)
+	}
+	if len(hs.Middlewares.Enabled) != 2 || hs.Middlewares.Enabled[1] != "recovery" {
+		t.Fatalf("Enabled=%q", hs.Middlewares.Enabled)
+	}
+
+	for _, bad := range []string{"HTTPServer.Listen", "HTTPServer.NotExist=1", "HTTPServer.ReadTimeout=abc", "HTTPServer.Listeners=a"} {
+		if err := applyOverrides(c, []string{bad}); err == nil {
+			t.Errorf("override %q should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+//go:build !windows
+
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 12:10:31
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 12:10:31
+ * @Description: 热重启, 将监听的socket交给新进程, 老进程处理完请求后退出
+ */
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	// 父进程传递给子进程的listener地址, 多个以逗号分隔, 按顺序对应从3开始的fd
+	envInheritListeners = "SIMPLE_BOOT_INHERIT_LISTENERS"
+	// 子进程启动完成后, 向该fd写入数据通知父进程
+	envReadyFD = "SIMPLE_BOOT_READY_FD"
+	// 0、1、2为标准输入输出, ExtraFiles从3开始
+	inheritFDStart = 3
+)
+
+var (
+	// RestartSignals 触发热重启的信号
+	RestartSignals = []os.Signal{syscall.SIGUSR2}
+	// RestartTimeout 等待新进程启动完成的最长时间, 超时后老进程继续提供服务
+	RestartTimeout = 30 * time.Second
+)
+
+// inherited 一个从父进程继承的listener
+type inherited struct {
+	addr     string
+	listener net.Listener
+	used     bool
+}
+
+var (
+	inheritedOnce      sync.Once
+	inheritedListeners []*inherited
+	inheritedErr       error
+)
+
+// inheritedListener 从父进程继承的listener, 没有则返回nil
+func inheritedListener(addr string) (net.Listener, error) {
+	inheritedOnce.Do(func() {
+		inheritedListeners, inheritedErr = loadInheritedListeners()
+	})
+	if inheritedErr != nil {
+		return nil, inheritedErr
+	}
+	for _, in := range inheritedListeners {
+		if !in.used && in.addr == addr {
+			in.used = true
+			return in.listener, nil
+		}
+	}
+	return nil, nil
+}
+
+// closeUnusedInherited 关闭新进程的配置中已经没有的监听, 在所有监听创建后调用
+// 否则老进程退出后, 这些端口仍被新进程占用却没有人accept
+func closeUnusedInherited() {
+	inheritedOnce.Do(func() {
+		inheritedListeners, inheritedErr = loadInheritedListeners()
+	})
+	for _, in := range inheritedListeners {
+		if in.used {
+			continue
+		}
+		in.used = true
+		log.Printf("[restart] inherited listener %s not used by any listener, closed\n", in.addr)
+		_ = in.listener.Close()
+	}
+}
+
+// loadInheritedListeners 按 SIMPLE_BOOT_INHERIT_LISTENERS 的顺序读取从3开始的fd
+func loadInheritedListeners() ([]*inherited, error) {
+	addrs := os.Getenv(envInheritListeners)
+	if addrs == "" {
+		return nil, nil
+	}
+	var listeners []*inherited
+	for i, a := range strings.Split(addrs, ",") {
+		f := os.NewFile(uintptr(inheritFDStart+i), "listener:"+a)
+		ln, err := net.FileListener(f)
+		_ = f.Close()
+		if err != nil {
+			for _, in := range listeners {
+				_ = in.listener.Close()
+			}
+			return nil, fmt.Errorf("inherit listener %q: %w", a, err)
+		}
+		listeners = append(listeners, &inherited{addr: a, listener: ln})
+	}
+	return listeners, nil
+}
+
+// notifyReady 通知父进程新进程已经开始提供服务
+func notifyReady() {
+	fdStr := os.Getenv(envReadyFD)
+	_ = os.Unsetenv(envReadyFD)
+	_ = os.Unsetenv(envInheritListeners)
+	if fdStr == "" {
+		return
+	}
+	fd, err := strconv.Atoi(fdStr)
+	if err != nil {
+		return
+	}
+	f := os.NewFile(uintptr(fd), "ready")
+	_, _ = f.Write([]byte{1})
+	_ = f.Close()
+}
+
+// restart 使用当前的启动命令拉起新进程, 并将所有listener传给它
+// 新进程通知启动完成后返回nil, 之后老进程应当优雅退出
+func (app *App) restart() error {
+	files := make([]*os.File, 0, len(app.servers)+1)
+	addrs := make([]string, 0, len(app.servers))
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+	for _, s := range app.servers {
+		filer, ok := s.rawListener.(interface{ File() (*os.File, error) })
+		if !ok {
+			return fmt.Errorf("listener %q %T not support hot restart", s.Name, s.rawListener)
+		}
+		f, err := filer.File()
+		if err != nil {
+			return err
+		}
+		files = append(files, f)
+		addrs = append(addrs, s.address())
+	}
+
+	readyR, readyW, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer readyR.Close()
+	readyFD := inheritFDStart + len(files)
+	files = append(files, readyW)
+
+	// 重新查找可执行文件, 这样可以启动新部署的二进制
+	path, err := exec.LookPath(os.Args[0])
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(path, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = files
+	cmd.Env = append(restartEnv(os.Environ()),
+		envInheritListeners+"="+strings.Join(addrs, ","),
+		envReadyFD+"="+strconv.Itoa(readyFD),
+	)
+	errStart := cmd.Start()
+	// 父进程不再持有写端, 子进程退出时读端能收到EOF
+	_ = readyW.Close()
+	files = files[:len(files)-1]
+	if errStart != nil {
+		return errStart
+	}
+
+	ready := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 1)
+		_, err := readyR.Read(buf)
+		ready <- err
+	}()
+	select {
+	case err = <-ready:
+	case <-time.After(RestartTimeout):
+		err = errors.New("wait for new process timeout")
+	}
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_, _ = cmd.Process.Wait()
+		return fmt.Errorf("new process pid=%d not ready: %w", cmd.Process.Pid, err)
+	}
+	// unix socket 文件已经交给新进程, 老进程关闭时不能删除
+	for _, s := range app.servers {
+		if ul, ok := s.rawListener.(*net.UnixListener); ok {
+			ul.SetUnlinkOnClose(false)
+		}
+	}
+	fmt.Fprintf(DefaultWriter, "[APP RESTART] New process pid=%d\n", cmd.Process.Pid)
+	return cmd.Process.Release()
+}
+
+// restartEnv 去掉当前进程上次继承时留下的环境变量
+func restartEnv(environ []string) []string {
+	ret := make([]string, 0, len(environ))
+	for _, kv := range environ {
+		if strings.HasPrefix(kv, envInheritListeners+"=") || strings.HasPrefix(kv, envReadyFD+"=") {
+			continue
+		}
+		ret = append(ret, kv)
+	}
+	return ret
+}
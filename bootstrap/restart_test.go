@@ -0,0 +1,115 @@
+//go:build !windows
+
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-19 16:05:12
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-19 16:05:12
+ * @Description: 热重启测试, 以测试二进制作为新进程
+ */
+package bootstrap
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// 设置时 TestHotRestart 作为新进程运行, 值为新进程配置的监听地址
+	envTestRestartChild = "SIMPLE_BOOT_TEST_RESTART_CHILD"
+)
+
+func TestHotRestart(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	if addr := os.Getenv(envTestRestartChild); addr != "" {
+		restartChild(t, addr)
+		return
+	}
+
+	// 老进程的两个监听, 新进程的配置中只保留了main
+	app := NewApp(context.Background(), &Config{}, gin.New())
+	app.servers = nil
+	for _, name := range []string{"main", "removed"} {
+		s := &server{ListenerConfig: ListenerConfig{Name: name, Listen: "127.0.0.1:0"}}
+		if err := s.listen(); err != nil {
+			t.Fatal(err)
+		}
+		// 按实际地址传递给新进程
+		s.Listen = s.rawListener.Addr().String()
+		defer s.rawListener.Close()
+		app.servers = append(app.servers, s)
+	}
+	mainAddr, removedAddr := app.servers[0].Listen, app.servers[1].Listen
+
+	// 新进程只运行本测试
+	args := os.Args
+	os.Args = []string{os.Args[0], "-test.run=^TestHotRestart$"}
+	defer func() { os.Args = args }()
+	t.Setenv(envTestRestartChild, mainAddr)
+	if err := app.restart(); err != nil {
+		t.Fatal(err)
+	}
+	// 同老进程退出, 之后只有新进程持有socket
+	for _, s := range app.servers {
+		_ = s.rawListener.Close()
+	}
+
+	resp, err := http.Get("http://" + mainAddr + "/pid")
+	if err != nil {
+		t.Fatalf("inherited listener: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if pid, _ := strconv.Atoi(string(body)); pid == 0 || pid == os.Getpid() {
+		t.Fatalf("served by pid %q, want the new process", body)
+	}
+	if conn, err := net.Dial("tcp", removedAddr); err == nil {
+		conn.Close()
+		t.Fatal("unused inherited listener not closed by the new process")
+	}
+
+	resp, err = http.Get("http://" + mainAddr + "/stop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		conn, err := net.Dial("tcp", mainAddr)
+		if err != nil {
+			break
+		}
+		conn.Close()
+		if time.Now().After(deadline) {
+			t.Fatal("new process not stopped")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// restartChild 新进程, 使用继承的listener提供服务, 直到收到 /stop
+func restartChild(t *testing.T, addr string) {
+	// 父进程测试失败时也不会一直运行
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	handler := gin.New()
+	handler.GET("/pid", func(c *gin.Context) {
+		c.String(http.StatusOK, strconv.Itoa(os.Getpid()))
+	})
+	handler.GET("/stop", func(c *gin.Context) {
+		cancel()
+	})
+	c := &Config{}
+	c.HTTPServer.Listen = addr
+	if err := NewApp(ctx, c, handler).Start(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,35 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 12:10:31
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 12:10:31
+ * @Description: windows 不支持热重启及socket activation
+ */
+package bootstrap
+
+import (
+	"errors"
+	"net"
+	"os"
+)
+
+// RestartSignals windows 下没有可用的信号
+var RestartSignals []os.Signal
+
+func inheritedListener(addr string) (net.Listener, error) {
+	return nil, nil
+}
+
+func activatedListener(name string, addr string) (net.Listener, error) {
+	return nil, nil
+}
+
+func closeUnusedInherited() {}
+
+func closeUnusedActivated() {}
+
+func notifyReady() {}
+
+func (app *App) restart() error {
+	return errors.New("hot restart is not supported on windows")
+}
@@ -0,0 +1,269 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 13:05:47
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 13:05:47
+ * @Description: 监听及其对应的http服务
+ */
+package bootstrap
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"golang.org/x/net/netutil"
+)
+
+const (
+	// HTTPServer.Listen 对应的监听名字
+	mainListenerName = "main"
+	// unix socket 的监听地址前缀, 如 unix:///run/app.sock
+	unixListenPrefix = "unix://"
+	// 关闭时检查h2c请求是否处理完成的间隔
+	h2cPollInterval = 10 * time.Millisecond
+)
+
+// ListenerConfig 一个监听的配置
+type ListenerConfig struct {
+	// Name 唯一的名字, 必选
+	Name string
+	// Listen 监听地址, 必选
+	// 如 :8443、127.0.0.1:9090、unix:///run/app.sock
+	Listen string
+	// TLS 是否为https
+	TLS bool
+	// Admin 是否使用独立的 admin gin engine, 即 AppServer.AdminHandler
+	Admin bool
+	// H2C 非https时是否支持 HTTP/2 cleartext, 用于内部服务间调用
+	H2C bool
+	// SocketMode unix socket文件的权限, 如 "0660", 为空时由umask决定
+	SocketMode string
+	// SocketGroup unix socket文件的用户组, 组名或gid, 为空时不修改
+	SocketGroup string
+}
+
+// server 一个监听及其http服务
+type server struct {
+	ListenerConfig
+	*http.Server
+	listener net.Listener
+	// 未经 MaxConns 限制包装的listener, 热重启时传递其fd
+	rawListener net.Listener
+	// 最大并发连接数, 0不限制
+	maxConns int
+	// tcp keep-alive的探测间隔, 0使用默认值, 小于0关闭
+	tcpKeepAlive time.Duration
+	// h2c连接被h2c.NewHandler接管, http.Server.Shutdown 不会等待其上的请求
+	h2cActive atomic.Int64
+}
+
+// listen 开始监听
+// 若是热重启拉起的新进程, 直接使用父进程传递过来的listener
+// 若是由systemd等按 LISTEN_FDS 协议启动, 使用传递过来的socket
+func (s *server) listen() error {
+	addr := s.address()
+	ln, err := inheritedListener(addr)
+	if err != nil {
+		return err
+	}
+	if ln == nil {
+		ln, err = activatedListener(s.Name, addr)
+		if err != nil {
+			return err
+		}
+	}
+	if ln != nil {
+		// 传递过来的listener不经过 net.ListenConfig, 需要自己设置keep-alive
+		s.rawListener = ln
+		s.listener = newKeepAliveListener(ln, s.tcpKeepAlive)
+	} else {
+		network, address := parseListen(addr)
+		if network == "unix" {
+			if err := removeStaleSocket(address); err != nil {
+				return fmt.Errorf("listener %q: %w", s.Name, err)
+			}
+		}
+		lc := net.ListenConfig{KeepAlive: s.tcpKeepAlive}
+		ln, err = lc.Listen(context.Background(), network, address)
+		if err != nil {
+			return fmt.Errorf("listener %q: %w", s.Name, err)
+		}
+		if network == "unix" {
+			if err := s.setSocketPerm(address); err != nil {
+				_ = ln.Close()
+				return fmt.Errorf("listener %q: %w", s.Name, err)
+			}
+		}
+		s.rawListener = ln
+		s.listener = ln
+	}
+	if s.maxConns > 0 {
+		s.listener = netutil.LimitListener(s.listener, s.maxConns)
+	}
+	return nil
+}
+
+// keepAliveListener 为接受的tcp连接设置keep-alive, 同 net.ListenConfig.KeepAlive
+type keepAliveListener struct {
+	*net.TCPListener
+	period time.Duration
+}
+
+// newKeepAliveListener 非tcp的listener原样返回
+func newKeepAliveListener(ln net.Listener, period time.Duration) net.Listener {
+	tcpLn, ok := ln.(*net.TCPListener)
+	if !ok {
+		return ln
+	}
+	return &keepAliveListener{TCPListener: tcpLn, period: period}
+}
+
+func (ln *keepAliveListener) Accept() (net.Conn, error) {
+	conn, err := ln.AcceptTCP()
+	if err != nil {
+		return nil, err
+	}
+	if ln.period < 0 {
+		_ = conn.SetKeepAlive(false)
+		return conn, nil
+	}
+	_ = conn.SetKeepAlive(true)
+	if ln.period > 0 {
+		_ = conn.SetKeepAlivePeriod(ln.period)
+	}
+	return conn, nil
+}
+
+// setSocketPerm 设置unix socket文件的权限及用户组
+func (s *server) setSocketPerm(path string) error {
+	if s.SocketMode != "" {
+		mode, err := strconv.ParseUint(s.SocketMode, 8, 32)
+		if err != nil {
+			return fmt.Errorf("invalid SocketMode %q: %w", s.SocketMode, err)
+		}
+		if err := os.Chmod(path, os.FileMode(mode)); err != nil {
+			return err
+		}
+	}
+	if s.SocketGroup != "" {
+		gid, err := lookupGroupID(s.SocketGroup)
+		if err != nil {
+			return err
+		}
+		if err := os.Chown(path, -1, gid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// serve 开始处理请求, 直到Shutdown
+func (s *server) serve(tlsConfig *tls.Config) error {
+	if s.TLS {
+		// 证书由 tlsConfig.GetCertificate 提供
+		s.TLSConfig = tlsConfig
+		return s.ServeTLS(s.listener, "", "")
+	}
+	if s.H2C {
+		h2s := &http2.Server{IdleTimeout: s.IdleTimeout}
+		// 注册Shutdown时的回调, 优雅关闭 HTTP/2 的连接
+		if err := http2.ConfigureServer(s.Server, h2s); err != nil {
+			return err
+		}
+		handler := s.Handler
+		s.Handler = h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ProtoMajor == 2 {
+				s.h2cActive.Add(1)
+				defer s.h2cActive.Add(-1)
+			}
+			handler.ServeHTTP(w, r)
+		}), h2s)
+	}
+	return s.Serve(s.listener)
+}
+
+// shutdown 优雅关闭, 等待所有请求(包括h2c连接上的请求)处理完成
+func (s *server) shutdown(ctx context.Context) error {
+	if err := s.Shutdown(ctx); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(h2cPollInterval)
+	defer ticker.Stop()
+	for s.h2cActive.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// address 监听地址, 为空时同 http.Server 使用默认端口
+func (s *server) address() string {
+	if s.Listen != "" {
+		return s.Listen
+	}
+	if s.TLS {
+		return ":https"
+	}
+	return ":http"
+}
+
+func (s *server) scheme() string {
+	if s.TLS {
+		return "HTTPS"
+	}
+	return "HTTP"
+}
+
+// parseListen 解析监听地址, 返回 net.Listen 所需的参数
+func parseListen(listen string) (network string, address string) {
+	if strings.HasPrefix(listen, unixListenPrefix) {
+		return "unix", strings.TrimPrefix(listen, unixListenPrefix)
+	}
+	return "tcp", listen
+}
+
+// lookupGroupID 用户组名或gid
+func lookupGroupID(group string) (int, error) {
+	if gid, err := strconv.Atoi(group); err == nil {
+		return gid, nil
+	}
+	g, err := user.LookupGroup(group)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(g.Gid)
+}
+
+// removeStaleSocket 进程异常退出时socket文件会残留, 导致无法监听
+// 只删除连接被拒绝的socket文件, 仍有进程在监听时返回错误, 不抢占其地址
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSocket == 0 {
+		return nil
+	}
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("listen unix %s: %w", path, syscall.EADDRINUSE)
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return fmt.Errorf("check unix socket %s: %w", path, err)
+	}
+	return os.Remove(path)
+}
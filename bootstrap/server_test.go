@@ -0,0 +1,52 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-19 10:12:40
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-19 10:12:40
+ * @Description: 监听测试
+ */
+package bootstrap
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+)
+
+func TestRemoveStaleSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix socket")
+	}
+	// t.TempDir 可能超过unix socket路径的长度限制
+	dir, err := os.MkdirTemp("", "sb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.sock")
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 仍在监听的不删除
+	if err := removeStaleSocket(path); !errors.Is(err, syscall.EADDRINUSE) {
+		t.Fatalf("live socket: %v", err)
+	}
+	// 模拟进程异常退出, socket文件残留
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = ln.Close()
+	if err := removeStaleSocket(path); err != nil {
+		t.Fatalf("stale socket: %v", err)
+	}
+	if _, err := os.Lstat(path); !os.IsNotExist(err) {
+		t.Fatalf("stale socket not removed: %v", err)
+	}
+	if err := removeStaleSocket(path); err != nil {
+		t.Fatalf("missing socket: %v", err)
+	}
+}
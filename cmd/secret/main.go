@@ -0,0 +1,94 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 23:31:05
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 23:31:05
+ * @Description: 生成主密钥及加密配置中的敏感内容
+ */
+
+// secret 生成主密钥, 或加密配置中的密码等内容
+//
+//	go run github.com/liziwei01/simple-boot/cmd/secret genkey
+//	export SIMPLE_BOOT_SECRET_KEY=...
+//	go run github.com/liziwei01/simple-boot/cmd/secret encrypt 'my password'
+//	echo -n 'my password' | go run github.com/liziwei01/simple-boot/cmd/secret encrypt
+//
+// encrypt 输出 {secret.密文}, 写在配置文件的字符串值中, 如 Password = "{secret.密文}"、yaml中 Password: "{secret.密文}"
+// 解析配置后才解密, 明文中的引号、反斜杠、冒号、换行等不需要转义
+// 挂载的密码文件使用 {file.路径}, 同样写在字符串值中, 解析后替换为文件内容
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/liziwei01/simple-boot/library/conf"
+)
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if err := run(flag.Args(), os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "secret:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), `usage:
+  secret genkey           print a new base64 encoded master key
+  secret encrypt [value]  encrypt value, or stdin when omitted, print {secret.<ciphertext>}
+
+the master key is read from env %s, or the file in env %s
+put the output inside a quoted string in the config, e.g. Password = "{secret.<ciphertext>}";
+it is decrypted after parsing, so the value needs no escaping.
+{file.<path>} for mounted secret files works the same way
+`, conf.EnvSecretKey, conf.EnvSecretKeyFile)
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		usage()
+		return fmt.Errorf("missing command")
+	}
+	switch args[0] {
+	case "genkey":
+		key, err := conf.GenerateSecretKey()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, key)
+		return nil
+	case "encrypt":
+		key, err := conf.SecretKey()
+		if err != nil {
+			return err
+		}
+		var plaintext string
+		switch len(args) {
+		case 1:
+			bf, err := io.ReadAll(stdin)
+			if err != nil {
+				return err
+			}
+			// echo 等会带上结尾的换行
+			plaintext = string(bytes.TrimRight(bf, "\r\n"))
+		case 2:
+			plaintext = args[1]
+		default:
+			return fmt.Errorf("encrypt accepts at most one value")
+		}
+		ciphertext, err := conf.EncryptSecret(key, plaintext)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "{secret.%s}\n", ciphertext)
+		return nil
+	default:
+		usage()
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
@@ -32,6 +32,7 @@ func newBeforeHelper(name string, fn BeforeFunc) *beforeHelper {
 }
 
 // defaultHelpers 默认的helper方法：获取环境变量
+// {secret.xxx}、{file.xxx} 在解析之后替换, 见 resolveSecrets, 其中可以使用 {env.xxx}, 如 {file.{env.SECRET_DIR|/run/secrets}/db}
 var defaultHelpers = []*beforeHelper{
 	newBeforeHelper("env", helperOsEnvVars),
 }
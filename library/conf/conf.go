@@ -20,8 +20,12 @@ import (
 type Conf interface {
 	// 读取并解析配置文件
 	// confName 支持相对路径和绝对路径
+	// 存在 <name>.<RunMode>.<后缀>、<name>.local.<后缀> 时依次深度合并到confName上再解析
+	// 解析后按 default、validate tag 设置默认值并校验, 错误见 FieldError
 	Parse(confName string, obj interface{}) error
-	// 解析bytes内容
+	// 解析confName时使用的所有文件, 按叠加的顺序
+	Sources(confName string) []string
+	// 解析bytes内容, 同样会设置默认值并校验
 	ParseBytes(fileExt string, content []byte, obj interface{}) error
 	// 配置文件是否存在
 	Exists(confName string) bool
@@ -33,6 +37,8 @@ type Conf interface {
 	RegisterBeforeFunc(name string, fn BeforeFunc) error
 	// 配置的环境信息
 	Env() env.AppEnv
+	// 复制一个使用环境e的实例, 包括已注册的parser及辅助方法
+	CloneWithEnv(e env.AppEnv) Conf
 }
 
 // New 创建一个新的配置解析实例
@@ -91,6 +97,9 @@ func (c *conf) parseByAbsPath(confAbsPath string, obj interface{}) (err error) {
 	if len(c.parsers) == 0 {
 		return fmt.Errorf("no parser found")
 	}
+	if sources := c.Sources(confAbsPath); len(sources) > 1 {
+		return c.readConfMerged(sources, obj)
+	}
 	return c.readConfDirect(confAbsPath, obj)
 }
 
@@ -100,9 +109,9 @@ func (c *conf) readConfDirect(confPath string, obj interface{}) error {
 	if errIO != nil {
 		return errIO
 	}
-	// 读取文件扩展名，现在支持.toml .json
+	// 读取文件扩展名，现在支持.toml .json .yaml .yml
 	fileExt := filepath.Ext(confPath)
-	return c.ParseBytes(fileExt, content, obj)
+	return c.parseBytes(fileExt, content, obj, confPath)
 }
 
 // 配置里面如果设置了环境就返回设置好的，没有就返回default环境
@@ -113,8 +122,26 @@ func (c *conf) Env() env.AppEnv {
 	return c.env
 }
 
+// 复制parser及辅助方法, 之后两个实例的注册互不影响
+func (c *conf) CloneWithEnv(e env.AppEnv) Conf {
+	c1 := &conf{
+		env:     e,
+		parsers: make(map[string]ParserFunc, len(c.parsers)),
+		helpers: append([]*beforeHelper(nil), c.helpers...),
+	}
+	for ext, fn := range c.parsers {
+		c1.parsers[ext] = fn
+	}
+	return c1
+}
+
 // 开始按照文件扩展名分配解析函数解析配置文件
 func (c *conf) ParseBytes(fileExt string, content []byte, obj interface{}) error {
+	return c.parseBytes(fileExt, content, obj, "")
+}
+
+// parseBytes 解析后设置默认值并校验, file用于错误信息
+func (c *conf) parseBytes(fileExt string, content []byte, obj interface{}, file string) error {
 	parserFn, hasParser := c.parsers[fileExt]
 	if fileExt == "" || !hasParser {
 		return fmt.Errorf("%w, fileExt %q is not supported yet", fmt.Errorf("no parser found"), fileExt)
@@ -124,9 +151,16 @@ func (c *conf) ParseBytes(fileExt string, content []byte, obj interface{}) error
 		return fmt.Errorf("%w, content=\n%s", errHelper, string(contentNew))
 	}
 	if errParser := parserFn(contentNew, obj); errParser != nil {
+		// 不输出解密后的内容
+		if hasSecrets(content) {
+			return fmt.Errorf("%w, content=\n%s", errParser, string(content))
+		}
 		return fmt.Errorf("%w, content=\n%s", errParser, string(contentNew))
 	}
-	return nil
+	if err := resolveSecrets(c, obj); err != nil {
+		return err
+	}
+	return applyTags(obj, file)
 }
 
 // executeBeforeHelpers 执行
@@ -146,11 +180,7 @@ func (c *conf) executeBeforeHelpers(input []byte, helpers []*beforeHelper) (outp
 
 // 检查该配置文件是否存在
 func (c *conf) Exists(confName string) bool {
-	info, err := os.Stat(c.confFileRealPath(confName))
-	if err != nil {
-		return false
-	}
-	return !info.IsDir()
+	return isFile(c.confFileRealPath(confName))
 }
 
 // 注册解析能力
@@ -21,6 +21,11 @@ func Parse(confName string, obj interface{}) (err error) {
 	return Default.Parse(confName, obj)
 }
 
+// Sources 解析confName时使用的所有文件, 如 app.toml、app.debug.toml、app.local.toml
+func Sources(confName string) []string {
+	return Default.Sources(confName)
+}
+
 // ParseBytes 解析bytes
 //
 // fileExt 是file extension 文件后缀，如.json、.toml
@@ -0,0 +1,49 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 21:42:30
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 21:42:30
+ * @Description: 按文件名查找配置文件
+ */
+package conf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// FindFile 在dir下按 FileExts 的顺序查找 name+后缀 的配置文件, 返回绝对路径
+// 如 FindFile("conf/servicer", "db") 依次尝试 db.toml、db.yaml、db.yml、db.json
+// 都不存在时返回的错误满足 errors.Is(err, os.ErrNotExist)
+func FindFile(dir string, name string) (string, error) {
+	for _, ext := range FileExts {
+		path, err := filepath.Abs(filepath.Join(dir, name+ext))
+		if err != nil {
+			return "", err
+		}
+		if isFile(path) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("conf file %s.{toml,yaml,yml,json} in %s: %w", name, dir, os.ErrNotExist)
+}
+
+// Glob dir下所有后缀在 FileExts 中的配置文件, 按文件名排序, 不包括叠加配置(见 IsOverlay)
+func Glob(dir string) ([]string, error) {
+	var files []string
+	for _, ext := range FileExts {
+		matches, err := filepath.Glob(filepath.Join(dir, "*"+ext))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if !IsOverlay(match) {
+				files = append(files, match)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
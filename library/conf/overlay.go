@@ -0,0 +1,144 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 22:10:12
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 22:10:12
+ * @Description: 按运行模式叠加配置
+ */
+package conf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/liziwei01/simple-boot/library/env"
+	"gopkg.in/yaml.v3"
+)
+
+// OverlayLocal 本地的叠加配置, 如 app.local.toml, 优先级最高, 不应提交到代码库
+const OverlayLocal = "local"
+
+// encodeFuncs 叠加后重新编码, 再使用对应的 ParserFunc 解析到结构体
+var encodeFuncs = map[string]func(v interface{}) ([]byte, error){
+	FileTOML: func(v interface{}) ([]byte, error) {
+		var buf bytes.Buffer
+		err := toml.NewEncoder(&buf).Encode(v)
+		return buf.Bytes(), err
+	},
+	FileJSON: json.Marshal,
+	FileYAML: yaml.Marshal,
+	FileYML:  yaml.Marshal,
+}
+
+// overlayPaths 文件的叠加配置, 按优先级从低到高, 如 app.toml 为 app.<runMode>.toml、app.local.toml
+func overlayPaths(path string, runMode string) []string {
+	ext := filepath.Ext(path)
+	name := strings.TrimSuffix(path, ext)
+	paths := make([]string, 0, 2)
+	if runMode != "" && runMode != OverlayLocal {
+		paths = append(paths, name+"."+runMode+ext)
+	}
+	return append(paths, name+"."+OverlayLocal+ext)
+}
+
+// IsOverlay path是否为其他配置文件的叠加配置, 即 <name>.<运行模式|local>.<后缀> 且 <name>.<后缀> 存在
+func IsOverlay(path string) bool {
+	ext := filepath.Ext(path)
+	name := strings.TrimSuffix(path, ext)
+	switch strings.TrimPrefix(filepath.Ext(name), ".") {
+	case OverlayLocal, env.RunModeDebug, env.RunModeTest, env.RunModeRelease:
+		return isFile(strings.TrimSuffix(name, filepath.Ext(name)) + ext)
+	}
+	return false
+}
+
+// Sources 解析confName时使用的所有文件, 按叠加的顺序, 文件不存在时为空
+func (c *conf) Sources(confName string) []string {
+	path := c.confFileRealPath(confName)
+	if !isFile(path) {
+		return nil
+	}
+	sources := []string{path}
+	for _, overlay := range overlayPaths(path, c.Env().RunMode()) {
+		if isFile(overlay) {
+			sources = append(sources, overlay)
+		}
+	}
+	return sources
+}
+
+// readConfMerged 分别解析各个文件, 深度合并后再解析到obj
+// map按key合并, 其他类型(包括数组)整体覆盖
+func (c *conf) readConfMerged(sources []string, obj interface{}) error {
+	fileExt := filepath.Ext(sources[0])
+	encodeFn, hasEncoder := encodeFuncs[fileExt]
+	if !hasEncoder {
+		return fmt.Errorf("fileExt %q does not support overlays, sources=%q", fileExt, sources)
+	}
+	merged := map[string]interface{}{}
+	var secret bool
+	for _, path := range sources {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		secret = secret || hasSecrets(content)
+		var layer map[string]interface{}
+		if err := c.ParseBytes(fileExt, content, &layer); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		mergeTree(merged, layer)
+	}
+	content, err := encodeFn(merged)
+	if err != nil {
+		return fmt.Errorf("encode merged sources=%q: %w", sources, err)
+	}
+	// 各个文件已经执行过 BeforeFunc 及 resolveSecrets, 替换后的内容由encodeFn转义
+	if err := c.parsers[fileExt](content, obj); err != nil {
+		// 不输出解密后的内容
+		if secret {
+			return fmt.Errorf("%w, sources=%q", err, sources)
+		}
+		return fmt.Errorf("%w, sources=%q, merged content=\n%s", err, sources, string(content))
+	}
+	return applyTags(obj, strings.Join(sources, "+"))
+}
+
+// mergeTree 将src深度合并到dst
+// 同解析到结构体时, key不区分大小写, dst中没有相同的key时合并到仅大小写不同的key上, 保留dst中的写法
+func mergeTree(dst map[string]interface{}, src map[string]interface{}) {
+	for k, v := range src {
+		k = foldKey(dst, k)
+		srcMap, srcIsMap := v.(map[string]interface{})
+		dstMap, dstIsMap := dst[k].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeTree(dstMap, srcMap)
+			continue
+		}
+		dst[k] = v
+	}
+}
+
+// foldKey m中与key相同的key, 没有时为仅大小写不同的key, 都没有时返回key
+func foldKey(m map[string]interface{}, key string) string {
+	if _, has := m[key]; has {
+		return key
+	}
+	for k := range m {
+		if strings.EqualFold(k, key) {
+			return k
+		}
+	}
+	return key
+}
+
+// isFile 文件存在且不是目录
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,126 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 22:10:12
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 22:10:12
+ * @Description: 叠加配置测试
+ */
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liziwei01/simple-boot/library/env"
+)
+
+type overlayConf struct {
+	Name   string
+	Listen string
+	Ports  []int
+	Server struct {
+		Timeout int
+		Debug   bool
+	}
+	Labels map[string]string
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	t.Setenv("OVERLAY_TEST_LISTEN", ":9000")
+	cases := map[string]map[string]string{
+		FileTOML: {
+			"app.toml":         "Name = \"app\"\nListen = \":8080\"\nPorts = [1, 2]\n[Server]\nTimeout = 10\n[Labels]\nzone = \"a\"\nowner = \"x\"\n",
+			"app.debug.toml":   "Ports = [3]\n[Server]\nDebug = true\n[Labels]\nzone = \"b\"\n",
+			"app.release.toml": "Name = \"release\"\n",
+			"app.local.toml":   "Listen = \"{env.OVERLAY_TEST_LISTEN}\"\n",
+		},
+		FileYAML: {
+			"app.yaml":         "Name: app\nListen: \":8080\"\nPorts: [1, 2]\nServer:\n  Timeout: 10\nLabels:\n  zone: a\n  owner: x\n",
+			"app.debug.yaml":   "Ports: [3]\nServer:\n  Debug: true\nLabels:\n  zone: b\n",
+			"app.release.yaml": "Name: release\n",
+			"app.local.yaml":   "Listen: \"{env.OVERLAY_TEST_LISTEN}\"\n",
+		},
+		FileJSON: {
+			"app.json":       `{"Name":"app","Listen":":8080","Ports":[1,2],"Server":{"Timeout":10},"Labels":{"zone":"a","owner":"x"}}`,
+			"app.debug.json": `{"Ports":[3],"Server":{"Debug":true},"Labels":{"zone":"b"}}`,
+			"app.local.json": `{"Listen":"{env.OVERLAY_TEST_LISTEN}"}`,
+		},
+	}
+	for ext, files := range cases {
+		dir := t.TempDir()
+		writeFiles(t, dir, files)
+		c := Default.CloneWithEnv(env.New(env.Option{RunMode: env.RunModeDebug, ConfDir: dir}))
+		name := "app" + ext
+		if sources := c.Sources(name); len(sources) != 3 || filepath.Base(sources[1]) != "app.debug"+ext {
+			t.Fatalf("%s: sources=%q", ext, sources)
+		}
+		var got overlayConf
+		if err := c.Parse(name, &got); err != nil {
+			t.Fatalf("%s: %v", ext, err)
+		}
+		// map合并, 数组整体覆盖
+		if got.Name != "app" || got.Listen != ":9000" || len(got.Ports) != 1 || got.Ports[0] != 3 ||
+			got.Server.Timeout != 10 || !got.Server.Debug || got.Labels["zone"] != "b" || got.Labels["owner"] != "x" {
+			t.Errorf("%s: got %+v", ext, got)
+		}
+		files, err := Glob(dir)
+		if err != nil || len(files) != 1 || filepath.Base(files[0]) != name {
+			t.Errorf("%s: glob %q %v", ext, files, err)
+		}
+	}
+
+	// 叠加配置中key的大小写与基础配置不同
+	mixedCase := map[string]map[string]string{
+		FileTOML: {
+			"mixed.toml":       "[resource]\nhost = \"base\"\nport = 3306\n",
+			"mixed.debug.toml": "[Resource]\nHost = \"local\"\n",
+		},
+		FileYAML: {
+			"mixed.yaml":       "resource:\n  host: base\n  port: 3306\n",
+			"mixed.debug.yaml": "Resource:\n  Host: local\n",
+		},
+		FileJSON: {
+			"mixed.json":       `{"resource":{"host":"base","port":3306}}`,
+			"mixed.debug.json": `{"RESOURCE":{"HOST":"local"}}`,
+		},
+	}
+	for ext, files := range mixedCase {
+		dir := t.TempDir()
+		writeFiles(t, dir, files)
+		c := Default.CloneWithEnv(env.New(env.Option{RunMode: env.RunModeDebug, ConfDir: dir}))
+		var got struct {
+			Resource struct {
+				Host string
+				Port int
+			}
+		}
+		if err := c.Parse("mixed"+ext, &got); err != nil {
+			t.Fatalf("%s mixed case: %v", ext, err)
+		}
+		if got.Resource.Host != "local" || got.Resource.Port != 3306 {
+			t.Errorf("%s mixed case: got %+v", ext, got)
+		}
+	}
+
+	// 没有叠加配置时直接解析
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"db.toml": "Name = \"db\"\n", "db.local.yaml": "Name: other\n"})
+	c := Default.CloneWithEnv(env.New(env.Option{RunMode: env.RunModeRelease, ConfDir: dir}))
+	var got overlayConf
+	if err := c.Parse("db.toml", &got); err != nil || got.Name != "db" || len(c.Sources("db.toml")) != 1 {
+		t.Errorf("db: %+v %v", got, err)
+	}
+	if len(c.Sources("none.toml")) != 0 {
+		t.Errorf("none: %q", c.Sources("none.toml"))
+	}
+}
@@ -3,7 +3,7 @@
  * @Date: 2022-03-04 15:41:55
  * @LastEditors: liziwei01
  * @LastEditTime: 2022-03-04 16:04:40
- * @Description: 支持 json 及 toml
+ * @Description: 支持 json、toml 及 yaml
  */
 package conf
 
@@ -15,7 +15,7 @@ import (
 )
 
 // ParserFunc 针对特定文件后缀的配置解析方法
-// 当前已经内置了 .toml  和 .json的解析方法
+// 当前已经内置了 .toml、.json、.yaml 和 .yml 的解析方法
 type ParserFunc func(bf []byte, obj interface{}) error
 
 const (
@@ -24,8 +24,15 @@ const (
 	FileTOML = ".toml"
 	// FileJSON  json
 	FileJSON = ".json"
+	// FileYAML yaml
+	FileYAML = ".yaml"
+	// FileYML yaml
+	FileYML = ".yml"
 )
 
+// FileExts 查找配置文件时按顺序尝试的后缀, 见 FindFile
+var FileExts = []string{FileTOML, FileYAML, FileYML, FileJSON}
+
 // stripComment 去除单行的'#'注释
 // 只支持单行，不支持行尾
 func stripComment(input []byte) (out []byte) {
@@ -45,6 +52,8 @@ func stripComment(input []byte) (out []byte) {
 var DefaultParserFuncs = map[string]ParserFunc{
 	FileJSON: JSONParserFunc,
 	FileTOML: TOMLParserFunc,
+	FileYAML: YAMLParserFunc,
+	FileYML:  YAMLParserFunc,
 }
 
 // 若内容以 # 开头，则该为注释
@@ -0,0 +1,236 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 23:31:05
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 23:31:05
+ * @Description: 配置中的加密内容及文件内容
+ */
+package conf
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+const (
+	// EnvSecretKey 解密 {secret.xxx} 的主密钥, 为base64编码的32字节, 优先于 EnvSecretKeyFile
+	EnvSecretKey = "SIMPLE_BOOT_SECRET_KEY"
+	// EnvSecretKeyFile 主密钥文件的路径, 文件内容同 EnvSecretKey
+	EnvSecretKeyFile = "SIMPLE_BOOT_SECRET_KEY_FILE"
+
+	// secretKeyLen AES-256
+	secretKeyLen = 32
+)
+
+var (
+	// 模板变量格式：{secret.密文}, 密文为 EncryptSecret 的结果
+	secretReg = regexp.MustCompile(`\{secret\.([A-Za-z0-9_-]+)\}`)
+	// 模板变量格式：{file.文件路径}, 相对路径相对于conf目录
+	fileReg = regexp.MustCompile(`\{file\.([^}]+)\}`)
+	// 同时匹配以上两种, 一次替换
+	secretOrFileReg = regexp.MustCompile(secretReg.String() + "|" + fileReg.String())
+)
+
+// ErrNoSecretKey 配置中有 {secret.xxx} 但没有设置主密钥
+var ErrNoSecretKey = errors.New("secret key not found, set env " + EnvSecretKey + " or " + EnvSecretKeyFile)
+
+// resolveSecrets 将obj中字符串值里的 {secret.xxx} 替换为解密后的内容, {file.xxx} 替换为文件的内容
+// 在解析之后执行, 替换的内容不经过toml、yaml、json的解析, 其中的引号、换行、冒号等不需要转义
+// 因此只能写在字符串值中, 如 Password = "{secret.xxx}", yaml中同样需要引号, 否则为map
+// 替换后的内容不会再次替换, 如文件内容中的 {secret.xxx}
+func resolveSecrets(c Conf, obj interface{}) error {
+	r := &secretResolver{conf: c}
+	r.walk(reflect.ValueOf(obj))
+	return errors.Join(r.errs...)
+}
+
+// secretResolver 遍历解析结果, 有 {secret.xxx} 时才读取主密钥
+type secretResolver struct {
+	conf   Conf
+	key    []byte
+	keyErr error
+	errs   []error
+}
+
+func (r *secretResolver) walk(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			r.walk(v.Elem())
+		}
+	case reflect.Interface:
+		// 接口中的值不能直接修改, 复制后处理再放回, 如 map[string]interface{} 中的值
+		if v.IsNil() || !v.CanSet() {
+			return
+		}
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		r.walk(elem)
+		v.Set(elem)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				r.walk(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			r.walk(v.Index(i))
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			r.walk(elem)
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	case reflect.String:
+		if v.CanSet() && secretOrFileReg.MatchString(v.String()) {
+			v.SetString(r.replace(v.String()))
+		}
+	}
+}
+
+// replace 一次替换s中所有的 {secret.xxx} 及 {file.xxx}
+func (r *secretResolver) replace(s string) string {
+	return secretOrFileReg.ReplaceAllStringFunc(s, func(subStr string) string {
+		m := secretOrFileReg.FindStringSubmatch(subStr)
+		if m[1] != "" {
+			return r.decrypt(subStr, m[1])
+		}
+		return r.readFile(subStr, m[2])
+	})
+}
+
+// decrypt 解密 {secret.xxx}, 失败时保留原样并记录错误
+func (r *secretResolver) decrypt(subStr string, ciphertext string) string {
+	if r.key == nil && r.keyErr == nil {
+		r.key, r.keyErr = SecretKey()
+		if r.keyErr != nil {
+			r.errs = append(r.errs, r.keyErr)
+		}
+	}
+	if r.keyErr != nil {
+		return subStr
+	}
+	plaintext, err := DecryptSecret(r.key, ciphertext)
+	if err != nil {
+		// 只输出密文的开头, 便于定位
+		r.errs = append(r.errs, fmt.Errorf("{secret.%.8s...}: %w", ciphertext, err))
+		return subStr
+	}
+	return plaintext
+}
+
+// readFile 读取 {file.xxx} 的内容, 去掉结尾的换行, 相对路径相对于conf目录
+// 用于 k8s secret、docker secret 挂载的文件, 如 {file./run/secrets/db_password}
+func (r *secretResolver) readFile(subStr string, path string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.conf.Env().ConfDir(), path)
+	}
+	bf, err := os.ReadFile(path)
+	if err != nil {
+		r.errs = append(r.errs, err)
+		return subStr
+	}
+	return string(bytes.TrimRight(bf, "\r\n"))
+}
+
+// hasSecrets 是否使用了 {secret.xxx} 或 {file.xxx}, 叠加配置合并后的内容中有替换后的明文, 出错时不能输出
+func hasSecrets(content []byte) bool {
+	return secretReg.Match(content) || fileReg.Match(content)
+}
+
+// SecretKey 从环境变量 EnvSecretKey 或 EnvSecretKeyFile 指定的文件读取主密钥
+func SecretKey() ([]byte, error) {
+	encoded := os.Getenv(EnvSecretKey)
+	if encoded == "" {
+		path := os.Getenv(EnvSecretKeyFile)
+		if path == "" {
+			return nil, ErrNoSecretKey
+		}
+		bf, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read secret key: %w", err)
+		}
+		encoded = string(bf)
+	}
+	return ParseSecretKey(encoded)
+}
+
+// ParseSecretKey 解析base64编码的主密钥
+func ParseSecretKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("secret key should be base64 encoded: %w", err)
+	}
+	if len(key) != secretKeyLen {
+		return nil, fmt.Errorf("secret key should be %d bytes, got %d", secretKeyLen, len(key))
+	}
+	return key, nil
+}
+
+// GenerateSecretKey 生成一个随机的主密钥, 返回base64编码的结果
+func GenerateSecretKey() (string, error) {
+	key := make([]byte, secretKeyLen)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// EncryptSecret 使用AES-GCM加密plaintext, 返回的密文可在配置中写为 {secret.密文}
+// 密文为 base64url(nonce + 加密结果), 每次加密的结果不同
+func EncryptSecret(key []byte, plaintext string) (string, error) {
+	aead, err := newSecretAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptSecret 解密 EncryptSecret 的结果
+func DecryptSecret(key []byte, ciphertext string) (string, error) {
+	aead, err := newSecretAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("invalid ciphertext: %w", err)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("invalid ciphertext: too short")
+	}
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		// 密钥不对或密文被修改
+		return "", errors.New("decrypt failed, wrong secret key or corrupted ciphertext")
+	}
+	return string(plaintext), nil
+}
+
+func newSecretAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
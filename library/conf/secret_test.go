@@ -0,0 +1,125 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 23:31:05
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 23:31:05
+ * @Description: 加密内容及文件内容测试
+ */
+package conf
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/liziwei01/simple-boot/library/env"
+)
+
+func TestSecrets(t *testing.T) {
+	encoded, err := GenerateSecretKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ParseSecretKey(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := EncryptSecret(key, "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"master.key": encoded + "\n",
+		"db_user":    "root\n",
+	})
+	t.Setenv(EnvSecretKey, "")
+	t.Setenv(EnvSecretKeyFile, filepath.Join(dir, "master.key"))
+	c := Default.CloneWithEnv(env.New(env.Option{ConfDir: dir}))
+
+	var got struct {
+		User     string
+		Password string
+	}
+	content := "User = \"{file.db_user}\"\nPassword = \"{secret." + ciphertext + "}\"\n"
+	if err := c.ParseBytes(FileTOML, []byte(content), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.User != "root" || got.Password != "hunter2" {
+		t.Fatalf("got %+v", got)
+	}
+
+	// 解密后的内容原样使用, 不受配置格式的转义规则影响
+	plaintext := "p\"a\\ss: *word\n\t{env.HOME}'"
+	special, err := EncryptSecret(key, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	placeholder := "{secret." + special + "}"
+	// 挂载的文件内容同样原样使用, 其中的 {secret.xxx} 不再解密
+	fileContent := "f\"i\\le: *x\nInjected = 1\n" + placeholder
+	writeFiles(t, dir, map[string]string{"db_token": fileContent + "\n"})
+	formats := map[string]string{
+		FileTOML: "Password = \"" + placeholder + "\"\nUser = 'x-" + placeholder + "'\nToken = \"{file.db_token}\"\n",
+		FileYAML: "Password: '" + placeholder + "'\nUser: \"x-" + placeholder + "\"\nToken: '{file.db_token}'\n",
+		FileJSON: `{"Password":"` + placeholder + `","User":"x-` + placeholder + `","Token":"{file.db_token}"}`,
+	}
+	for ext, content := range formats {
+		var got struct {
+			User     string
+			Password string
+			Token    string
+			Injected int
+		}
+		if err := c.ParseBytes(ext, []byte(content), &got); err != nil {
+			t.Fatalf("%s: %v", ext, err)
+		}
+		if got.Password != plaintext || got.User != "x-"+plaintext || got.Token != fileContent || got.Injected != 0 {
+			t.Errorf("%s: got %+v", ext, got)
+		}
+	}
+	// 叠加配置合并时也不受影响, map中的值同样解密
+	writeFiles(t, dir, map[string]string{
+		"db.toml":       "User = \"root\"\n",
+		"db.local.toml": "Password = \"" + placeholder + "\"\n[Extra]\ntoken = \"" + placeholder + "\"\n",
+	})
+	var merged struct {
+		User     string
+		Password string
+		Extra    map[string]interface{}
+	}
+	if err := c.Parse("db.toml", &merged); err != nil {
+		t.Fatal(err)
+	}
+	if merged.User != "root" || merged.Password != plaintext || merged.Extra["token"] != plaintext {
+		t.Errorf("merged: %+v", merged)
+	}
+
+	// 解析失败时不输出解密后的内容
+	var wrongType struct {
+		Password int
+	}
+	if err := c.ParseBytes(FileTOML, []byte(content), &wrongType); err == nil || strings.Contains(err.Error(), "hunter2") {
+		t.Errorf("err=%v", err)
+	}
+
+	// 密钥不对或没有密钥
+	other, _ := GenerateSecretKey()
+	t.Setenv(EnvSecretKey, other)
+	if err := c.ParseBytes(FileTOML, []byte(content), &got); err == nil || !strings.Contains(err.Error(), "decrypt failed") {
+		t.Errorf("err=%v", err)
+	}
+	t.Setenv(EnvSecretKey, "")
+	t.Setenv(EnvSecretKeyFile, "")
+	if err := c.ParseBytes(FileTOML, []byte(content), &got); !errors.Is(err, ErrNoSecretKey) {
+		t.Errorf("err=%v", err)
+	}
+	if err := c.ParseBytes(FileTOML, []byte(`User = "{file.none}"`), &got); err == nil {
+		t.Error("missing file should fail")
+	}
+	if _, err := ParseSecretKey("c2hvcnQ="); err == nil {
+		t.Error("short key should fail")
+	}
+}
@@ -0,0 +1,187 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 23:02:16
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 23:02:16
+ * @Description: 解析后按 default、validate tag 设置默认值及校验
+ */
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// TagDefault 默认值的tag, 解析后字段仍为零值时使用, 切片使用 , 分隔, time.Duration 可使用 10s 的格式
+	// 无法区分未配置与配置为零值, 默认值不为零值时, 配置中显式的 false、0、"" 也会被默认值替换,
+	// 需要配置为零值的字段应使用指针类型(默认值只在nil时设置)或不设置默认值
+	TagDefault = "default"
+	// TagValidate 校验的tag, 规则同 github.com/go-playground/validator, 如 required,min=1,oneof=a b
+	TagValidate = "validate"
+)
+
+// FieldError 字段的默认值或校验错误, 多个字段的错误使用 errors.Join 合并
+type FieldError struct {
+	// File 配置文件, 有叠加配置时为使用 + 连接的所有文件, ParseBytes 时为空
+	File string
+	// Path 字段的路径, 如 Resource.Manual.Host、Listeners[0].Addr
+	Path string
+	Msg  string
+}
+
+func (e *FieldError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("%s: %s", e.Path, e.Msg)
+	}
+	return fmt.Sprintf("%s: %s: %s", e.File, e.Path, e.Msg)
+}
+
+var (
+	validateOnce sync.Once
+	validate     *validator.Validate
+)
+
+// applyTags 设置默认值后校验obj, obj不是结构体(的指针)时跳过
+func applyTags(obj interface{}, file string) error {
+	v := reflect.ValueOf(obj)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || !v.CanAddr() {
+		return nil
+	}
+	var errs []error
+	applyDefaults(v, "", file, &errs)
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	validateOnce.Do(func() {
+		validate = validator.New(validator.WithRequiredStructEnabled())
+		validate.SetTagName(TagValidate)
+	})
+	err := validate.Struct(v.Addr().Interface())
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+	for _, fe := range fieldErrs {
+		// 去掉开头的结构体名, 如 Config.Resource.Manual.Host
+		_, path, _ := strings.Cut(fe.StructNamespace(), ".")
+		errs = append(errs, &FieldError{File: file, Path: path, Msg: validateMsg(fe)})
+	}
+	return errors.Join(errs...)
+}
+
+// validateMsg 校验失败的说明, 只输出规则, 不输出值
+// 值可能是密码等敏感信息, 切片、map中也可能包含
+func validateMsg(fe validator.FieldError) string {
+	rule := fe.Tag()
+	if fe.Param() != "" {
+		rule += "=" + fe.Param()
+	}
+	if rule == "required" {
+		return "is required"
+	}
+	return "should be " + rule
+}
+
+// applyDefaults 为结构体v中仍为零值且有default tag的字段设置默认值, 递归处理结构体、指针及切片
+// nil的指针及map中的值不处理
+func applyDefaults(v reflect.Value, path string, file string, errs *[]error) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			applyDefaults(v.Elem(), path, file, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			applyDefaults(v.Index(i), fmt.Sprintf("%s[%d]", path, i), file, errs)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			fieldPath := f.Name
+			if path != "" {
+				fieldPath = path + "." + f.Name
+			}
+			field := v.Field(i)
+			if def, has := f.Tag.Lookup(TagDefault); has && field.IsZero() {
+				if err := setDefault(field, def); err != nil {
+					*errs = append(*errs, &FieldError{File: file, Path: fieldPath, Msg: fmt.Sprintf("default %q: %v", def, err)})
+					continue
+				}
+			}
+			applyDefaults(field, fieldPath, file, errs)
+		}
+	}
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setDefault 将字符串转换为字段的类型
+func setDefault(v reflect.Value, value string) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		return setDefault(v.Elem(), value)
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Type() == durationType {
+			if d, err := time.ParseDuration(value); err == nil {
+				v.SetInt(int64(d))
+				return nil
+			}
+		}
+		i, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		parts := strings.Split(value, ",")
+		s := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setDefault(s.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
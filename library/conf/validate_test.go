@@ -0,0 +1,96 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 23:02:16
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 23:02:16
+ * @Description: 默认值及校验测试
+ */
+package conf
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/liziwei01/simple-boot/library/env"
+)
+
+type tagListener struct {
+	Addr    string `validate:"required"`
+	Network string `default:"tcp" validate:"oneof=tcp unix"`
+}
+
+type tagConf struct {
+	Name     string        `validate:"required"`
+	RunMode  string        `default:"release" validate:"oneof=debug test release"`
+	Retry    int           `default:"2" validate:"min=0,max=5"`
+	Timeout  time.Duration `default:"1500ms"`
+	Enabled  []string      `default:"a, b"`
+	Resource struct {
+		Manual struct {
+			Host string `validate:"required"`
+			Port int    `default:"3306" validate:"min=1,max=65535"`
+		}
+	}
+	Listeners []tagListener `validate:"dive"`
+	Tokens    []string      `validate:"max=1"`
+	// 指针可以配置为false, 不被默认值替换
+	Verify   *bool `default:"true"`
+	Optional *struct {
+		Port int `default:"80"`
+	}
+}
+
+func TestTags(t *testing.T) {
+	var c *tagConf
+	content := "Name = \"db\"\nRetry = 0\nVerify = false\n[Resource.Manual]\nHost = \"127.0.0.1\"\n[[Listeners]]\nAddr = \":80\"\n"
+	if err := ParseBytes(FileTOML, []byte(content), &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.RunMode != "release" || c.Retry != 2 || c.Timeout != 1500*time.Millisecond || len(c.Enabled) != 2 || c.Enabled[1] != "b" ||
+		c.Resource.Manual.Port != 3306 || c.Listeners[0].Network != "tcp" || c.Optional != nil || c.Verify == nil || *c.Verify {
+		t.Fatalf("got %+v", c)
+	}
+
+	// 所有错误一起返回, 带有文件及字段路径
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"db.toml": "RunMode = \"prod\"\nRetry = 9\nTokens = [\"secret-a\", \"secret-b\"]\n[Resource.Manual]\nPort = 70000\n[[Listeners]]\nNetwork = \"udp\"\n",
+	})
+	conf := Default.CloneWithEnv(env.New(env.Option{RunMode: env.RunModeTest, ConfDir: dir}))
+	var bad tagConf
+	err := conf.Parse("db.toml", &bad)
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.File != filepath.Join(dir, "db.toml") {
+		t.Fatalf("err=%v", err)
+	}
+	for _, want := range []string{
+		"db.toml: Name: is required",
+		"RunMode: should be oneof=debug test release",
+		"Retry: should be max=5",
+		"Resource.Manual.Host: is required",
+		"Resource.Manual.Port: should be max=65535",
+		"Listeners[0].Addr: is required",
+		"Listeners[0].Network: should be oneof=tcp unix",
+		"Tokens: should be max=1",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("err=%v, want %q", err, want)
+		}
+	}
+	// 不输出配置的值
+	for _, value := range []string{"got", "70000", "secret"} {
+		if strings.Contains(err.Error(), value) {
+			t.Errorf("err=%v, should not contain %q", err, value)
+		}
+	}
+
+	var badDefault struct {
+		Port int `default:"abc"`
+	}
+	if err := ParseBytes(FileJSON, []byte(`{}`), &badDefault); err == nil || !strings.Contains(err.Error(), `Port: default "abc"`) {
+		t.Errorf("err=%v", err)
+	}
+}
@@ -0,0 +1,204 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 22:35:47
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 22:35:47
+ * @Description: 配置文件热加载
+ */
+package conf
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultWatchInterval 默认检查文件变化的间隔
+	DefaultWatchInterval = time.Second
+	// DefaultWatchDebounce 默认的防抖时间, 文件在这段时间内不再变化才重新解析
+	DefaultWatchDebounce = 200 * time.Millisecond
+)
+
+// Validator 配置对象实现该接口时, 热加载解析成功后会校验, 失败时保留旧的配置
+type Validator interface {
+	Validate() error
+}
+
+// WatchOptions 热加载的选项
+type WatchOptions struct {
+	// Interval 检查文件变化的间隔, 默认 DefaultWatchInterval
+	Interval time.Duration
+	// Debounce 防抖时间, 默认 DefaultWatchDebounce, 连续写入时只在写完后解析一次
+	Debounce time.Duration
+}
+
+// Watcher 热加载的配置, 通过 Load 获取当前的快照
+type Watcher[T any] struct {
+	conf     Conf
+	confName string
+	path     string
+	onChange func(old *T, new *T)
+	opt      WatchOptions
+
+	value atomic.Pointer[T]
+	// 保证 Reload 串行执行
+	mu        sync.Mutex
+	signature string
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Watch 使用 Default 解析confName到obj, 之后定期检查文件(包括叠加配置)的变化并重新解析
+//
+//	w, err := conf.Watch("feature.toml", &FeatureConf{}, func(old, new *FeatureConf) {...})
+//	w.Load().Enabled
+//
+// 每次重新解析到一个新的零值对象, 成功后原子替换快照再调用onChange(可为nil)
+// 按文件内容判断变化, 每个Interval读取一次文件
+// 解析或校验(见 Validator)失败时保留旧的快照并打印日志
+func Watch[T any](confName string, obj *T, onChange func(old *T, new *T)) (*Watcher[T], error) {
+	return NewWatcher(Default, confName, obj, onChange, WatchOptions{})
+}
+
+// NewWatcher 同 Watch, 使用指定的Conf及选项
+func NewWatcher[T any](c Conf, confName string, obj *T, onChange func(old *T, new *T), opt WatchOptions) (*Watcher[T], error) {
+	if opt.Interval <= 0 {
+		opt.Interval = DefaultWatchInterval
+	}
+	if opt.Debounce <= 0 {
+		opt.Debounce = DefaultWatchDebounce
+	}
+	w := &Watcher[T]{
+		conf:     c,
+		confName: confName,
+		onChange: onChange,
+		opt:      opt,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	sources := c.Sources(confName)
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("conf file %q: %w", confName, os.ErrNotExist)
+	}
+	w.path = sources[0]
+	// 先取文件的状态再解析, 解析期间的修改会在下次检查时发现
+	w.signature = w.currentSignature()
+	if err := parseAndValidate(c, confName, obj); err != nil {
+		return nil, err
+	}
+	w.value.Store(obj)
+	go w.run()
+	return w, nil
+}
+
+// Load 当前的配置快照, 不要修改返回的对象
+func (w *Watcher[T]) Load() *T {
+	return w.value.Load()
+}
+
+// Reload 立即重新解析, 失败时保留旧的快照并返回错误
+// onChange 在释放锁后调用, 其中可以再调用 Reload
+func (w *Watcher[T]) Reload() error {
+	w.mu.Lock()
+	w.signature = w.currentSignature()
+	obj := new(T)
+	if err := parseAndValidate(w.conf, w.confName, obj); err != nil {
+		w.mu.Unlock()
+		return err
+	}
+	old := w.value.Swap(obj)
+	w.mu.Unlock()
+	if w.onChange != nil {
+		w.onChange(old, obj)
+	}
+	return nil
+}
+
+// Close 停止检查文件变化, 快照仍然可用
+func (w *Watcher[T]) Close() {
+	select {
+	case <-w.stop:
+	default:
+		close(w.stop)
+	}
+	<-w.done
+}
+
+// run 定期检查文件变化, 变化后等待文件稳定再重新解析
+func (w *Watcher[T]) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.opt.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+		w.mu.Lock()
+		changed := w.currentSignature() != w.signature
+		w.mu.Unlock()
+		if !changed || !w.waitStable() {
+			continue
+		}
+		if err := w.Reload(); err != nil {
+			log.Printf("[conf] reload %q has error, keep using old config: %v\n", w.confName, err)
+			continue
+		}
+		log.Printf("[conf] reload %q success\n", w.confName)
+	}
+}
+
+// waitStable 等待文件在Debounce时间内不再变化, 停止时返回false
+func (w *Watcher[T]) waitStable() bool {
+	last := w.currentSignature()
+	for {
+		select {
+		case <-w.stop:
+			return false
+		case <-time.After(w.opt.Debounce):
+		}
+		signature := w.currentSignature()
+		if signature == last {
+			return true
+		}
+		last = signature
+	}
+}
+
+// currentSignature 配置文件及可能的叠加配置内容的hash, 叠加配置新增或删除时也会变化
+// 不使用大小及修改时间, 大小不变的修改可能在修改时间的精度内发生
+func (w *Watcher[T]) currentSignature() string {
+	var b strings.Builder
+	paths := append([]string{w.path}, overlayPaths(w.path, w.conf.Env().RunMode())...)
+	for _, path := range paths {
+		// 软连接读取的是目标文件, 如 k8s configmap 的挂载
+		content, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(&b, "%s|-;", path)
+			continue
+		}
+		fmt.Fprintf(&b, "%s|%x;", path, sha256.Sum256(content))
+	}
+	return b.String()
+}
+
+// parseAndValidate 解析并校验obj
+func parseAndValidate(c Conf, confName string, obj interface{}) error {
+	if err := c.Parse(confName, obj); err != nil {
+		return err
+	}
+	if v, ok := obj.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("validate %q: %w", confName, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 22:35:47
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 22:35:47
+ * @Description: 热加载测试
+ */
+package conf
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/liziwei01/simple-boot/library/env"
+)
+
+type watchConf struct {
+	Timeout int
+	Enabled bool
+}
+
+func (c *watchConf) Validate() error {
+	if c.Timeout < 0 {
+		return errors.New("Timeout should not be negative")
+	}
+	return nil
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"feature.toml": "Timeout = 100\n"})
+	c := Default.CloneWithEnv(env.New(env.Option{RunMode: env.RunModeTest, ConfDir: dir}))
+
+	changes := make(chan [2]*watchConf, 10)
+	initial := &watchConf{}
+	w, err := NewWatcher(c, "feature.toml", initial, func(old, new *watchConf) {
+		changes <- [2]*watchConf{old, new}
+	}, WatchOptions{Interval: 10 * time.Millisecond, Debounce: 30 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if w.Load() != initial || initial.Timeout != 100 {
+		t.Fatalf("initial %+v", w.Load())
+	}
+	wait := func() [2]*watchConf {
+		t.Helper()
+		select {
+		case change := <-changes:
+			return change
+		case <-time.After(3 * time.Second):
+			t.Fatal("no change")
+			return [2]*watchConf{}
+		}
+	}
+
+	// 连续写入只解析一次
+	for _, content := range []string{"Timeout = 1\n", "Timeout = 12\n", "Timeout = 200\nEnabled = true\n"} {
+		writeFiles(t, dir, map[string]string{"feature.toml": content})
+		time.Sleep(5 * time.Millisecond)
+	}
+	change := wait()
+	if change[0] != initial || change[1].Timeout != 200 || !change[1].Enabled || w.Load() != change[1] {
+		t.Fatalf("change %+v %+v", change[0], change[1])
+	}
+
+	// 新增叠加配置
+	writeFiles(t, dir, map[string]string{"feature.local.toml": "Enabled = false\n"})
+	if change = wait(); change[1].Enabled || change[1].Timeout != 200 {
+		t.Fatalf("overlay %+v", change[1])
+	}
+
+	// 解析或校验失败时保留旧的配置
+	current := w.Load()
+	for _, bad := range []string{"Timeout = \"abc\"\n", "Timeout = -1\n"} {
+		writeFiles(t, dir, map[string]string{"feature.toml": bad})
+		if err := w.Reload(); err == nil {
+			t.Errorf("%q should fail", bad)
+		}
+	}
+	time.Sleep(100 * time.Millisecond)
+	if w.Load() != current || len(changes) != 0 {
+		t.Fatalf("bad config replaced the snapshot: %+v", w.Load())
+	}
+
+	if _, err := NewWatcher(c, "none.toml", &watchConf{}, nil, WatchOptions{}); err == nil {
+		t.Error("watching a missing file should fail")
+	}
+}
+
+func TestWatchSameSizeAndReloadInCallback(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "feature.toml")
+	writeFiles(t, dir, map[string]string{"feature.toml": "Timeout = 100\n"})
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := Default.CloneWithEnv(env.New(env.Option{RunMode: env.RunModeTest, ConfDir: dir}))
+
+	var (
+		watcher  atomic.Pointer[Watcher[watchConf]]
+		reloaded atomic.Bool
+	)
+	changes := make(chan *watchConf, 10)
+	w, err := NewWatcher(c, "feature.toml", &watchConf{}, func(old, new *watchConf) {
+		// 回调中调用 Reload 不会死锁
+		if !reloaded.Swap(true) {
+			if err := watcher.Load().Reload(); err != nil {
+				t.Error(err)
+			}
+		}
+		changes <- new
+	}, WatchOptions{Interval: 10 * time.Millisecond, Debounce: 30 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	watcher.Store(w)
+
+	// 大小及修改时间都不变的修改
+	writeFiles(t, dir, map[string]string{"feature.toml": "Timeout = 300\n"})
+	if err := os.Chtimes(path, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case change := <-changes:
+			if change.Timeout != 300 {
+				t.Fatalf("change %+v", change)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatal("no change")
+		}
+	}
+}
@@ -0,0 +1,229 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 21:42:30
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 21:42:30
+ * @Description: 支持 yaml
+ */
+package conf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLParserFunc .yaml、.yml配置文件格式解析函数
+// 多个文档(以---分隔)时: obj为slice的指针时每个文档为一个元素, 否则按顺序解析到obj, 后面的覆盖前面的
+// 同toml、json, key与结构体字段名的匹配不区分大小写, 如 Name、name 均对应字段 Name
+// 解析失败时返回 *YAMLError, 有多处错误时为 errors.Join 的结果
+var YAMLParserFunc ParserFunc = yamlParserFunc
+
+// YAMLError yaml解析失败的位置, Column为0表示未知
+type YAMLError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *YAMLError) Error() string {
+	if e.Column > 0 {
+		return fmt.Sprintf("yaml: line %d, column %d: %s", e.Line, e.Column, e.Msg)
+	}
+	return fmt.Sprintf("yaml: line %d: %s", e.Line, e.Msg)
+}
+
+var (
+	// yaml.v3 的语法错误, 如 yaml: line 3: mapping values are not allowed in this context
+	yamlSyntaxErrReg = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+	// yaml.v3 的类型错误, 如 line 3: cannot unmarshal !!str `abc` into int
+	yamlTypeErrReg = regexp.MustCompile(`^line (\d+): (.*)$`)
+	// 类型错误中出错的值
+	yamlTypeErrValueReg = regexp.MustCompile("`([^`]*)`")
+)
+
+func yamlParserFunc(bf []byte, obj interface{}) error {
+	dec := yaml.NewDecoder(bytes.NewReader(bf))
+	target := reflect.ValueOf(obj)
+	isSlice := target.Kind() == reflect.Pointer && target.Elem().Kind() == reflect.Slice
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return yamlSyntaxError(err)
+		}
+		// 只有注释的空文档
+		if len(doc.Content) == 0 || doc.Content[0].ShortTag() == "!!null" {
+			continue
+		}
+		out := obj
+		var elem reflect.Value
+		if isSlice {
+			elem = reflect.New(target.Elem().Type().Elem())
+			out = elem.Interface()
+		}
+		if out != nil {
+			foldKeys(&doc, reflect.TypeOf(out))
+		}
+		if err := doc.Decode(out); err != nil {
+			return yamlTypeError(err, &doc)
+		}
+		if isSlice {
+			target.Elem().Set(reflect.Append(target.Elem(), elem.Elem()))
+		}
+	}
+}
+
+// foldKeys 将与结构体字段名仅大小写不同的key改为yaml.v3使用的key
+// 没有yaml tag时yaml.v3只认全小写的字段名
+func foldKeys(n *yaml.Node, t reflect.Type) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch n.Kind {
+	case yaml.DocumentNode:
+		for _, c := range n.Content {
+			foldKeys(c, t)
+		}
+	case yaml.SequenceNode:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for _, c := range n.Content {
+				foldKeys(c, t.Elem())
+			}
+		}
+	case yaml.MappingNode:
+		switch t.Kind() {
+		case reflect.Map:
+			for i := 1; i < len(n.Content); i += 2 {
+				foldKeys(n.Content[i], t.Elem())
+			}
+		case reflect.Struct:
+			fields := yamlFields(t)
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				key := n.Content[i]
+				if key.Kind != yaml.ScalarNode {
+					continue
+				}
+				ft, has := fields[key.Value]
+				if !has {
+					for name, t1 := range fields {
+						if strings.EqualFold(name, key.Value) {
+							key.Value, ft, has = name, t1, true
+							break
+						}
+					}
+				}
+				if has {
+					foldKeys(n.Content[i+1], ft)
+				}
+			}
+		}
+	}
+}
+
+// yamlFields 结构体中yaml.v3使用的key及字段的类型, 包括 ,inline 的字段
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if strings.Contains(","+opts+",", ",inline,") && f.Type.Kind() == reflect.Struct {
+			for k, v := range yamlFields(f.Type) {
+				fields[k] = v
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// yamlSyntaxError yaml.v3 的语法错误只有行号
+func yamlSyntaxError(err error) error {
+	m := yamlSyntaxErrReg.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+	line, _ := strconv.Atoi(m[1])
+	return &YAMLError{Line: line, Msg: m[2]}
+}
+
+// yamlTypeError 按行号及出错的值在文档中找到列号
+func yamlTypeError(err error, doc *yaml.Node) error {
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return err
+	}
+	errs := make([]error, 0, len(typeErr.Errors))
+	for _, msg := range typeErr.Errors {
+		m := yamlTypeErrReg.FindStringSubmatch(msg)
+		if m == nil {
+			errs = append(errs, errors.New(stripYAMLValue(msg)))
+			continue
+		}
+		line, _ := strconv.Atoi(m[1])
+		var value string
+		if v := yamlTypeErrValueReg.FindStringSubmatch(m[2]); v != nil {
+			value = v[1]
+		}
+		errs = append(errs, &YAMLError{Line: line, Column: findColumn(doc, line, value), Msg: stripYAMLValue(m[2])})
+	}
+	return errors.Join(errs...)
+}
+
+// stripYAMLValue 去掉错误信息中出错的值, 值可能是解密后的密码, 只保留行列号及目标类型
+// 如 cannot unmarshal !!str `hunter2` into int 变为 cannot unmarshal !!str into int
+func stripYAMLValue(msg string) string {
+	return strings.Join(strings.Fields(yamlTypeErrValueReg.ReplaceAllString(msg, "")), " ")
+}
+
+// findColumn 行号为line的节点的列号, 有多个时优先值以value开头的(yaml.v3会截断过长的值)
+func findColumn(n *yaml.Node, line int, value string) int {
+	var first, matched int
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		if n.Line == line && n.Kind != yaml.DocumentNode {
+			if first == 0 {
+				first = n.Column
+			}
+			if matched == 0 && value != "" && n.Kind == yaml.ScalarNode && hasValuePrefix(n.Value, value) {
+				matched = n.Column
+			}
+		}
+		for _, c := range n.Content {
+			walk(c)
+		}
+	}
+	walk(n)
+	if matched > 0 {
+		return matched
+	}
+	return first
+}
+
+// hasValuePrefix 过长的值被截断为前7个字符加...
+func hasValuePrefix(v string, shown string) bool {
+	if prefix, ok := strings.CutSuffix(shown, "..."); ok && len(v) > 10 {
+		return strings.HasPrefix(v, prefix)
+	}
+	return v == shown
+}
@@ -0,0 +1,118 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 21:42:30
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 21:42:30
+ * @Description: yaml解析测试
+ */
+package conf
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type yamlServicer struct {
+	Name         string
+	WriteTimeOut int
+	Resource     struct {
+		Manual struct {
+			Host string
+			Port int
+		}
+	}
+	Tags map[string]string `yaml:"labels"`
+}
+
+func TestYAML(t *testing.T) {
+	t.Setenv("YAML_TEST_HOST", "10.0.0.1")
+	content := `# servicer
+Name: db
+writetimeout: 100
+Resource:
+  Manual:
+    Host: "{env.YAML_TEST_HOST}"
+    Port: {env.YAML_TEST_PORT|3306}
+labels:
+  Zone: a
+`
+	var s yamlServicer
+	if err := ParseBytes(FileYAML, []byte(content), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "db" || s.WriteTimeOut != 100 || s.Resource.Manual.Host != "10.0.0.1" || s.Resource.Manual.Port != 3306 || s.Tags["Zone"] != "a" {
+		t.Fatalf("got %+v", s)
+	}
+
+	// 多个文档: slice时每个文档一个元素, 否则后面的覆盖前面的
+	multi := "---\nName: a\nWriteTimeOut: 1\n---\n# empty\n---\nName: b\n"
+	var list []yamlServicer
+	if err := ParseBytes(FileYML, []byte(multi), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Name != "a" || list[1].Name != "b" || list[1].WriteTimeOut != 0 {
+		t.Fatalf("got %+v", list)
+	}
+	var merged yamlServicer
+	if err := ParseBytes(FileYML, []byte(multi), &merged); err != nil {
+		t.Fatal(err)
+	}
+	if merged.Name != "b" || merged.WriteTimeOut != 1 {
+		t.Fatalf("got %+v", merged)
+	}
+}
+
+func TestYAMLError(t *testing.T) {
+	cases := []struct {
+		content string
+		want    []string
+	}{
+		{"Name: db\nResource:\n  Manual:\n    Port: abc\n", []string{"yaml: line 4, column 11: cannot unmarshal !!str into int"}},
+		{"Name: db\nWriteTimeOut: 1\nWriteTimeOut: [1]\n", []string{"line 3, column 1"}},
+		{"WriteTimeOut: x\nResource:\n  Manual:\n    Port: a very long value\n", []string{"line 1, column 15", "line 4, column 11: cannot unmarshal !!str into int"}},
+		{"Name: db\n  Port: 1\n", []string{"yaml: line 2: mapping values are not allowed in this context"}},
+	}
+	for _, tc := range cases {
+		var s yamlServicer
+		err := ParseBytes(FileYAML, []byte(tc.content), &s)
+		var yamlErr *YAMLError
+		if err == nil || !errors.As(err, &yamlErr) {
+			t.Errorf("%q: err=%v", tc.content, err)
+			continue
+		}
+		for _, want := range tc.want {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("%q: err=%v, want %q", tc.content, err, want)
+			}
+		}
+		// 不输出出错的值
+		if strings.Contains(err.Error(), "`") {
+			t.Errorf("%q: err=%v contains the value", tc.content, err)
+		}
+	}
+}
+
+func TestFindFile(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"db.yaml", "db.json", "cache.toml", "cache.yml"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("Name: x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if path, err := FindFile(dir, "db"); err != nil || filepath.Base(path) != "db.yaml" {
+		t.Errorf("db: %s %v", path, err)
+	}
+	if path, err := FindFile(dir, "cache"); err != nil || filepath.Base(path) != "cache.toml" {
+		t.Errorf("cache: %s %v", path, err)
+	}
+	if _, err := FindFile(dir, "none"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("none: %v", err)
+	}
+	files, err := Glob(dir)
+	if err != nil || len(files) != 4 || filepath.Base(files[0]) != "cache.toml" {
+		t.Errorf("glob: %v %v", files, err)
+	}
+}
@@ -0,0 +1,40 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 21:15:08
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 21:15:08
+ * @Description: 默认的错误码注册中心
+ */
+package errs
+
+// Default 默认的实例, Define 及 resp 均使用该对象
+var Default = New()
+
+// Define 定义一个错误并注册到 Default, 错误码重复时panic, 在包级变量中使用
+func Define(code int, status int, message string) *Error {
+	e := &Error{Code: code, Status: status, Message: message}
+	if err := Default.Register(e); err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Lookup 按错误码查找
+func Lookup(code int) (*Error, bool) {
+	return Default.Lookup(code)
+}
+
+// Codes 所有注册的错误, 按错误码排序
+func Codes() []*Error {
+	return Default.Codes()
+}
+
+// LoadMessages 加载lang的本地化信息
+func LoadMessages(lang string, confPath string) error {
+	return Default.LoadMessages(lang, confPath)
+}
+
+// Localize e在langs下的本地化信息
+func Localize(e *Error, langs ...string) string {
+	return Default.Localize(e, langs...)
+}
@@ -0,0 +1,113 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 21:15:08
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 21:15:08
+ * @Description: 带错误码的业务错误
+ */
+
+// Package errs 业务错误, 包含错误码、http状态码、信息、详情及原因
+//
+//	var ErrUserNotFound = errs.Define(10001, http.StatusNotFound, "user not found")
+//
+//	return nil, ErrUserNotFound.Wrap(err)
+//
+// 内置的错误码与http状态码相同, 业务的错误码建议从10000开始
+// 信息可以按语言本地化, 见 Registry.LoadMessages
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Error 业务错误, 由 Define 定义, 使用时通过 Wrap、WithMessage、WithDetails 复制出新的错误
+type Error struct {
+	// Code 错误码, 唯一
+	Code int
+	// Status http状态码
+	Status int
+	// Message 返回给客户端的信息
+	Message string
+	// Details 返回给客户端的详情, 如校验失败的字段
+	Details interface{}
+
+	// 原因, 只打印日志, 不返回给客户端
+	cause error
+	// Message 被 WithMessage 修改过, 不再本地化
+	custom bool
+}
+
+// 内置的错误
+var (
+	ErrBadRequest            = Define(http.StatusBadRequest, http.StatusBadRequest, "bad request")
+	ErrUnauthorized          = Define(http.StatusUnauthorized, http.StatusUnauthorized, "unauthorized")
+	ErrForbidden             = Define(http.StatusForbidden, http.StatusForbidden, "forbidden")
+	ErrNotFound              = Define(http.StatusNotFound, http.StatusNotFound, "not found")
+	ErrRequestEntityTooLarge = Define(http.StatusRequestEntityTooLarge, http.StatusRequestEntityTooLarge, "request body too large")
+	ErrTooManyRequests       = Define(http.StatusTooManyRequests, http.StatusTooManyRequests, "too many requests")
+	ErrInternal              = Define(http.StatusInternalServerError, http.StatusInternalServerError, "internal server error")
+	ErrServiceUnavailable    = Define(http.StatusServiceUnavailable, http.StatusServiceUnavailable, "service unavailable")
+	ErrGatewayTimeout        = Define(http.StatusGatewayTimeout, http.StatusGatewayTimeout, "request timeout")
+)
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("[%d] %s: %v", e.Code, e.Message, e.cause)
+	}
+	return fmt.Sprintf("[%d] %s", e.Code, e.Message)
+}
+
+// Unwrap 返回原因
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is 错误码相同即认为是同一个错误, errors.Is(err, ErrUserNotFound)
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// StatusCode http状态码, 没有设置时为500
+func (e *Error) StatusCode() int {
+	if e.Status == 0 {
+		return http.StatusInternalServerError
+	}
+	return e.Status
+}
+
+// Wrap 复制一个原因为cause的错误
+func (e *Error) Wrap(cause error) *Error {
+	c := *e
+	c.cause = cause
+	return &c
+}
+
+// WithMessage 复制一个信息为指定内容的错误, 该信息不再本地化
+func (e *Error) WithMessage(format string, args ...interface{}) *Error {
+	c := *e
+	c.Message = fmt.Sprintf(format, args...)
+	c.custom = true
+	return &c
+}
+
+// WithDetails 复制一个带详情的错误
+func (e *Error) WithDetails(details interface{}) *Error {
+	c := *e
+	c.Details = details
+	return &c
+}
+
+// From 将err转为*Error, 错误链中没有*Error时作为 ErrInternal 的原因
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return ErrInternal.Wrap(err)
+}
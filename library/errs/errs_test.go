@@ -0,0 +1,99 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 21:15:08
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 21:15:08
+ * @Description: 业务错误测试
+ */
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestError(t *testing.T) {
+	r := New()
+	notFound := &Error{Code: 10001, Status: http.StatusNotFound, Message: "user not found"}
+	if err := r.Register(notFound); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(&Error{Code: 10001}); err == nil {
+		t.Fatal("duplicate code should fail")
+	}
+
+	cause := errors.New("sql: no rows in result set")
+	err := fmt.Errorf("get user 7: %w", notFound.Wrap(cause).WithDetails(map[string]int{"id": 7}))
+	if !errors.Is(err, notFound) || !errors.Is(err, cause) {
+		t.Fatalf("errors.Is failed: %v", err)
+	}
+	e := From(err)
+	if e.Code != 10001 || e.StatusCode() != http.StatusNotFound || e.Details == nil {
+		t.Fatalf("From: %+v", e)
+	}
+	if got := err.Error(); got != "get user 7: [10001] user not found: sql: no rows in result set" {
+		t.Fatalf("Error()=%q", got)
+	}
+	// 定义的错误不被修改
+	if notFound.Details != nil || notFound.Unwrap() != nil {
+		t.Fatal("defined error modified")
+	}
+	if e := From(cause); e.Code != ErrInternal.Code || !errors.Is(e, cause) {
+		t.Fatalf("From(plain)=%v", e)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "zh.toml"), []byte(`10001 = "用户不存在"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadMessagesDir(r, dir); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		e     *Error
+		langs []string
+		want  string
+	}{
+		{notFound, []string{"fr", "zh-CN"}, "用户不存在"},
+		{notFound.Wrap(cause), []string{"ZH_cn"}, "用户不存在"},
+		{notFound, []string{"en"}, "user not found"},
+		{notFound, nil, "user not found"},
+		{notFound.WithMessage("user %d not found", 7), []string{"zh"}, "user 7 not found"},
+	}
+	for _, c := range cases {
+		if got := r.Localize(c.e, c.langs...); got != c.want {
+			t.Errorf("Localize(%v, %v)=%q, want %q", c.e, c.langs, got, c.want)
+		}
+	}
+
+	// 复制的实例加载信息不影响原来的实例
+	r1 := r.Clone()
+	if err := r1.LoadMessages("zh", writeMessages(t, `10001 = "查无此人"`)); err != nil {
+		t.Fatal(err)
+	}
+	if got, got1 := r.Localize(notFound, "zh"), r1.Localize(notFound, "zh"); got != "用户不存在" || got1 != "查无此人" {
+		t.Fatalf("clone: %q %q", got, got1)
+	}
+	if e, has := r1.Lookup(10001); !has || e != notFound {
+		t.Fatal("clone lost registered codes")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "en.toml"), []byte(`abc = "x"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadMessagesDir(r, dir); err == nil {
+		t.Fatal("invalid code should fail")
+	}
+}
+
+func writeMessages(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "messages.toml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
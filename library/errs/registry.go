@@ -0,0 +1,159 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 21:15:08
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 21:15:08
+ * @Description: 错误码注册及本地化信息
+ */
+package errs
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/liziwei01/simple-boot/library/conf"
+)
+
+// Registry 一个app的错误码
+type Registry interface {
+	// Register 注册一个错误码, 不能重复
+	Register(e *Error) error
+	// Lookup 按错误码查找
+	Lookup(code int) (*Error, bool)
+	// Codes 所有注册的错误, 按错误码排序
+	Codes() []*Error
+	// LoadMessages 从配置文件加载lang的本地化信息, 内容为 错误码 = "信息", 如
+	//	10001 = "用户不存在"
+	LoadMessages(lang string, confPath string) error
+	// Localize e在langs中第一个有本地化信息的语言下的信息, 都没有时为 e.Message
+	// 语言如 zh-CN 没有时也会查找 zh
+	Localize(e *Error, langs ...string) string
+	// Clone 复制错误码及本地化信息到一个新实例, 如测试中加载的信息不影响 Default
+	Clone() Registry
+}
+
+// New 创建一个新的错误码注册中心
+func New() Registry {
+	return &registry{
+		codes:    map[int]*Error{},
+		messages: map[string]map[int]string{},
+	}
+}
+
+type registry struct {
+	mu    sync.RWMutex
+	codes map[int]*Error
+	// 语言 -> 错误码 -> 信息
+	messages map[string]map[int]string
+}
+
+func (r *registry) Register(e *Error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e1, has := r.codes[e.Code]; has {
+		return fmt.Errorf("error code %d already registered: %q", e.Code, e1.Message)
+	}
+	r.codes[e.Code] = e
+	return nil
+}
+
+func (r *registry) Lookup(code int) (*Error, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, has := r.codes[code]
+	return e, has
+}
+
+func (r *registry) Codes() []*Error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := make([]*Error, 0, len(r.codes))
+	for _, e := range r.codes {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+	return list
+}
+
+func (r *registry) LoadMessages(lang string, confPath string) error {
+	var data map[string]string
+	if err := conf.Parse(confPath, &data); err != nil {
+		return err
+	}
+	messages := make(map[int]string, len(data))
+	for key, msg := range data {
+		code, err := strconv.Atoi(key)
+		if err != nil {
+			return fmt.Errorf("%s: invalid error code %q", confPath, key)
+		}
+		messages[code] = msg
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages[normalizeLang(lang)] = messages
+	return nil
+}
+
+func (r *registry) Localize(e *Error, langs ...string) string {
+	if e.custom {
+		return e.Message
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, lang := range langs {
+		lang = normalizeLang(lang)
+		if msg, has := r.messages[lang][e.Code]; has {
+			return msg
+		}
+		if base, _, ok := strings.Cut(lang, "-"); ok {
+			if msg, has := r.messages[base][e.Code]; has {
+				return msg
+			}
+		}
+	}
+	return e.Message
+}
+
+func (r *registry) Clone() Registry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	r1 := &registry{
+		codes:    make(map[int]*Error, len(r.codes)),
+		messages: make(map[string]map[int]string, len(r.messages)),
+	}
+	for code, e := range r.codes {
+		r1.codes[code] = e
+	}
+	// LoadMessages 整体替换一个语言的信息, 不修改已有的map, 可以共用
+	for lang, messages := range r.messages {
+		r1.messages[lang] = messages
+	}
+	return r1
+}
+
+// LoadMessagesDir 加载dir下所有的 <语言>.toml, 如 zh-CN.toml, 也支持 conf.FileExts 中的其他格式
+func LoadMessagesDir(r Registry, dir string) error {
+	files, err := conf.Glob(dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		lang := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		if err := r.LoadMessages(lang, file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeLang 语言不区分大小写, zh_CN 同 zh-CN
+func normalizeLang(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
+
+// 为了在编译期即确保实现了接口
+var _ Registry = (*registry)(nil)
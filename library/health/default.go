@@ -0,0 +1,30 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 15:40:26
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 15:40:26
+ * @Description: 默认的健康检查注册中心
+ */
+package health
+
+import (
+	"context"
+)
+
+// Default 默认的实例, library中的组件及 /healthz /readyz 均使用该对象
+var Default = New()
+
+// Register 注册一项检查到默认实例
+func Register(c Check) error {
+	return Default.Register(c)
+}
+
+// RegisterFunc 注册一项只参与就绪检查的检查, 使用默认的超时及缓存时间
+func RegisterFunc(name string, fn func(ctx context.Context) error) error {
+	return Default.Register(Check{Name: name, Checker: CheckerFunc(fn)})
+}
+
+// Unregister 从默认实例取消一项检查
+func Unregister(name string) {
+	Default.Unregister(name)
+}
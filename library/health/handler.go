@@ -0,0 +1,42 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 15:40:26
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 15:40:26
+ * @Description: 健康检查的http接口
+ */
+package health
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liziwei01/simple-boot/library/env"
+)
+
+// LivenessHandler 存活检查接口, 如 /healthz
+func LivenessHandler(r Registry) gin.HandlerFunc {
+	return reportHandler(r.Liveness)
+}
+
+// ReadinessHandler 就绪检查接口, 如 /readyz
+func ReadinessHandler(r Registry) gin.HandlerFunc {
+	return reportHandler(r.Readiness)
+}
+
+// reportHandler 全部通过返回200, 否则返回503
+// 线上(release)只输出整体状态, 其他运行等级输出每一项检查的详情
+func reportHandler(run func(ctx context.Context) Report) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := run(c.Request.Context())
+		code := http.StatusOK
+		if !report.OK() {
+			code = http.StatusServiceUnavailable
+		}
+		if env.RunMode() == env.RunModeRelease {
+			report.Checks = nil
+		}
+		c.JSON(code, report)
+	}
+}
@@ -0,0 +1,236 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 15:40:26
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 15:40:26
+ * @Description: 健康检查
+ */
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// StatusOK 检查通过
+	StatusOK = "ok"
+	// StatusFail 检查失败
+	StatusFail = "fail"
+)
+
+var (
+	// DefaultTimeout 单次检查的默认超时时间
+	DefaultTimeout = time.Second
+	// DefaultCacheTTL 检查结果默认缓存时间, 避免探针频繁访问下游
+	DefaultCacheTTL = 3 * time.Second
+)
+
+// Checker 健康检查接口
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc 函数形式的Checker
+type CheckerFunc func(ctx context.Context) error
+
+// Check 实现Checker接口
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Check 一项健康检查
+type Check struct {
+	// Name 唯一的名字, 必选, 如 mysql.db_lib_user
+	Name string
+	// Checker 检查方法, 必选
+	Checker Checker
+	// Timeout 单次检查超时时间, 默认为 DefaultTimeout
+	Timeout time.Duration
+	// CacheTTL 结果缓存时间, 默认为 DefaultCacheTTL, 小于0不缓存
+	CacheTTL time.Duration
+	// Liveness 是否参与存活检查
+	// 默认只参与就绪检查, 下游故障时不应该导致进程被重启
+	Liveness bool
+}
+
+// Result 一项检查的结果
+type Result struct {
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Latency   string    `json:"latency"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report 所有检查的汇总
+type Report struct {
+	Status string   `json:"status"`
+	Checks []Result `json:"checks,omitempty"`
+}
+
+// OK 是否所有检查都通过
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+// Registry 健康检查注册中心
+type Registry interface {
+	// 注册一项检查, 名字不能重复
+	Register(c Check) error
+	// 取消一项检查
+	Unregister(name string)
+	// 存活检查, 只执行 Liveness=true 的检查
+	Liveness(ctx context.Context) Report
+	// 就绪检查, 执行所有的检查
+	Readiness(ctx context.Context) Report
+}
+
+// New 创建一个新的健康检查注册中心
+func New() Registry {
+	return &registry{
+		checks: map[string]*entry{},
+	}
+}
+
+type registry struct {
+	mu     sync.RWMutex
+	checks map[string]*entry
+}
+
+// entry 检查及其缓存的结果
+type entry struct {
+	Check
+	// 同一时刻只有一个检查在执行, 其他的等待并复用结果
+	mu     sync.Mutex
+	result Result
+}
+
+func (r *registry) Register(c Check) error {
+	if c.Name == "" {
+		return fmt.Errorf("check name is empty, not allow")
+	}
+	if c.Checker == nil {
+		return fmt.Errorf("check=%q has no checker", c.Name)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, has := r.checks[c.Name]; has {
+		return fmt.Errorf("check=%q already exists", c.Name)
+	}
+	r.checks[c.Name] = &entry{Check: c}
+	return nil
+}
+
+func (r *registry) Unregister(name string) {
+	r.mu.Lock()
+	delete(r.checks, name)
+	r.mu.Unlock()
+}
+
+func (r *registry) Liveness(ctx context.Context) Report {
+	return r.run(ctx, true)
+}
+
+func (r *registry) Readiness(ctx context.Context) Report {
+	return r.run(ctx, false)
+}
+
+// run 并发执行所有的检查
+func (r *registry) run(ctx context.Context, livenessOnly bool) Report {
+	r.mu.RLock()
+	entries := make([]*entry, 0, len(r.checks))
+	for _, e := range r.checks {
+		if livenessOnly && !e.Liveness {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	r.mu.RUnlock()
+
+	results := make([]Result, len(entries))
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Add(1)
+		go func(i int, e *entry) {
+			defer wg.Done()
+			results[i] = e.run(ctx)
+		}(i, e)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	report := Report{
+		Status: StatusOK,
+		Checks: results,
+	}
+	for _, res := range results {
+		if res.Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+// run 执行检查, 缓存未过期时直接返回缓存的结果
+func (e *entry) run(ctx context.Context) Result {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ttl := e.CacheTTL
+	if ttl == 0 {
+		ttl = DefaultCacheTTL
+	}
+	if ttl > 0 && !e.result.CheckedAt.IsZero() && time.Since(e.result.CheckedAt) < ttl {
+		return e.result
+	}
+
+	timeout := e.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := e.check(ctx)
+	res := Result{
+		Name:      e.Name,
+		Status:    StatusOK,
+		Latency:   time.Since(start).String(),
+		CheckedAt: start,
+	}
+	if err != nil {
+		res.Status = StatusFail
+		res.Error = err.Error()
+	}
+	e.result = res
+	return res
+}
+
+// check 带超时执行, 超时后不再等待Checker返回
+func (e *entry) check(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if re := recover(); re != nil {
+				done <- fmt.Errorf("panic: %v", re)
+			}
+		}()
+		done <- e.Checker.Check(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// 为了在编译期即确保实现了接口
+var _ Registry = (*registry)(nil)
@@ -0,0 +1,64 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 15:40:26
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 15:40:26
+ * @Description: 健康检查测试
+ */
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReport(t *testing.T) {
+	r := New()
+	var calls int32
+	if err := r.Register(Check{Name: "live", Liveness: true, Checker: CheckerFunc(func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(Check{Name: "db", Checker: CheckerFunc(func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(Check{Name: "db", Checker: CheckerFunc(func(ctx context.Context) error { return nil })}); err == nil {
+		t.Fatal("duplicate name should fail")
+	}
+
+	live := r.Liveness(context.Background())
+	if !live.OK() || len(live.Checks) != 1 {
+		t.Fatalf("liveness=%+v", live)
+	}
+	ready := r.Readiness(context.Background())
+	if ready.OK() || len(ready.Checks) != 2 || ready.Checks[0].Name != "db" || ready.Checks[0].Error != "connection refused" {
+		t.Fatalf("readiness=%+v", ready)
+	}
+	// 结果被缓存
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("calls=%d, want 1", n)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	r := New()
+	_ = r.Register(Check{Name: "slow", Timeout: 20 * time.Millisecond, CacheTTL: -1, Checker: CheckerFunc(func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})})
+	start := time.Now()
+	report := r.Readiness(context.Background())
+	if report.OK() || report.Checks[0].Error != context.DeadlineExceeded.Error() {
+		t.Fatalf("report=%+v", report)
+	}
+	if cost := time.Since(start); cost > 500*time.Millisecond {
+		t.Fatalf("cost=%s, timeout not applied", cost)
+	}
+}
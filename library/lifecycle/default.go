@@ -0,0 +1,30 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 11:02:15
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 11:02:15
+ * @Description: 默认的生命周期注册中心
+ */
+package lifecycle
+
+import (
+	"context"
+)
+
+// Default 默认的实例, library中的组件及bootstrap均使用该对象
+var Default = New()
+
+// Register 注册一个hook到默认实例
+func Register(h Hook) error {
+	return Default.Register(h)
+}
+
+// OnStart 注册一个只有启动回调的hook
+func OnStart(name string, fn func(ctx context.Context) error) error {
+	return Default.Register(Hook{Name: name, OnStart: fn})
+}
+
+// OnStop 注册一个只有退出回调的hook
+func OnStop(name string, fn func(ctx context.Context) error) error {
+	return Default.Register(Hook{Name: name, OnStop: fn})
+}
@@ -0,0 +1,259 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 11:02:15
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 11:02:15
+ * @Description: 组件生命周期管理
+ */
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout 单个hook的默认超时时间
+var DefaultTimeout = 10 * time.Second
+
+// ErrStarted 已经启动后不允许再注册
+var ErrStarted = errors.New("lifecycle already started")
+
+// Hook 生命周期钩子
+type Hook struct {
+	// Name 唯一的名字, 必选
+	Name string
+	// DependsOn 依赖的其他hook, 被依赖的先启动、后关闭
+	DependsOn []string
+	// OnStart 启动时执行, 可为空
+	OnStart func(ctx context.Context) error
+	// OnStop 退出时执行, 可为空
+	OnStop func(ctx context.Context) error
+	// Timeout OnStart、OnStop各自的超时时间, 默认为 DefaultTimeout
+	// 超时后不再等待, 但hook仍在自己的goroutine中运行直到返回, 应在ctx结束后尽快返回
+	Timeout time.Duration
+}
+
+// Registry 生命周期注册中心
+type Registry interface {
+	// 注册一个hook, 名字不能重复
+	Register(h Hook) error
+	// 按依赖顺序执行所有的OnStart
+	// 任意一个失败, 将倒序关闭已启动的hook, 并返回所有的错误
+	Start(ctx context.Context) error
+	// 倒序执行所有已启动hook的OnStop, 返回所有的错误
+	// 若未调用过Start, 将倒序关闭所有的hook
+	Stop(ctx context.Context) error
+	// 按依赖排好序的hook名字
+	Names() ([]string, error)
+	// 复制已注册的hook到一个未启动的新实例, 如测试中保留library在init时注册到 Default 的hook
+	Clone() Registry
+}
+
+// New 创建一个新的生命周期注册中心
+func New() Registry {
+	return &registry{}
+}
+
+type registry struct {
+	mu      sync.Mutex
+	hooks   []*Hook
+	started []*Hook
+	running bool
+	// 已经执行过Stop, 避免重复关闭
+	stopped bool
+}
+
+func (r *registry) Register(h Hook) error {
+	if h.Name == "" {
+		return fmt.Errorf("hook name is empty, not allow")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.running {
+		return fmt.Errorf("register hook=%q: %w", h.Name, ErrStarted)
+	}
+	for _, h1 := range r.hooks {
+		if h1.Name == h.Name {
+			return fmt.Errorf("hook=%q already exists", h.Name)
+		}
+	}
+	r.hooks = append(r.hooks, &h)
+	return nil
+}
+
+// Start 持有锁时只取排好序的hook, 执行hook时不持有锁, hook中调用Register会返回 ErrStarted 而不是死锁
+func (r *registry) Start(ctx context.Context) error {
+	r.mu.Lock()
+	if r.running {
+		r.mu.Unlock()
+		return ErrStarted
+	}
+	ordered, err := r.resolve()
+	if err != nil {
+		r.mu.Unlock()
+		return err
+	}
+	r.running = true
+	r.stopped = false
+	r.started = nil
+	r.mu.Unlock()
+
+	for _, h := range ordered {
+		if err := call(ctx, h, h.OnStart, "OnStart"); err != nil {
+			// 已经启动的倒序关闭
+			return errors.Join(err, r.Stop(ctx))
+		}
+		r.mu.Lock()
+		// 启动期间已经执行了Stop, 该hook不在Stop关闭的范围内, 单独关闭
+		if r.stopped {
+			r.mu.Unlock()
+			errStop := call(ctx, h, h.OnStop, "OnStop")
+			return errors.Join(fmt.Errorf("hook=%q OnStart: stopped during start", h.Name), errStop)
+		}
+		r.started = append(r.started, h)
+		r.mu.Unlock()
+	}
+	return nil
+}
+
+func (r *registry) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	if r.stopped {
+		r.mu.Unlock()
+		return nil
+	}
+	started := r.started
+	if !r.running {
+		ordered, err := r.resolve()
+		if err != nil {
+			r.mu.Unlock()
+			return err
+		}
+		started = ordered
+	}
+	r.started = nil
+	r.running = false
+	r.stopped = true
+	r.mu.Unlock()
+	return stop(ctx, started)
+}
+
+// stop 倒序关闭started, 不持有锁
+func stop(ctx context.Context, started []*Hook) error {
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		h := started[i]
+		if err := call(ctx, h, h.OnStop, "OnStop"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *registry) Names() ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ordered, err := r.resolve()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(ordered))
+	for _, h := range ordered {
+		names = append(names, h.Name)
+	}
+	return names, nil
+}
+
+// Clone 只复制hook, 不复制启动状态
+func (r *registry) Clone() Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r1 := &registry{hooks: make([]*Hook, 0, len(r.hooks))}
+	for _, h := range r.hooks {
+		h1 := *h
+		r1.hooks = append(r1.hooks, &h1)
+	}
+	return r1
+}
+
+// resolve 按依赖关系排序, 没有依赖关系的保持注册顺序
+// 依赖不存在或者循环依赖将返回错误
+func (r *registry) resolve() ([]*Hook, error) {
+	byName := make(map[string]*Hook, len(r.hooks))
+	for _, h := range r.hooks {
+		byName[h.Name] = h
+	}
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(r.hooks))
+	ordered := make([]*Hook, 0, len(r.hooks))
+
+	var visit func(h *Hook, path []string) error
+	visit = func(h *Hook, path []string) error {
+		switch state[h.Name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("hook dependency cycle: %v", append(path, h.Name))
+		}
+		state[h.Name] = visiting
+		for _, dep := range h.DependsOn {
+			depHook, has := byName[dep]
+			if !has {
+				return fmt.Errorf("hook=%q depends on %q, which is not registered", h.Name, dep)
+			}
+			if err := visit(depHook, append(path, h.Name)); err != nil {
+				return err
+			}
+		}
+		state[h.Name] = visited
+		ordered = append(ordered, h)
+		return nil
+	}
+	for _, h := range r.hooks {
+		if err := visit(h, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// call 带超时执行hook, 超时后不再等待fn返回, fn仍在goroutine中运行直到返回
+func call(ctx context.Context, h *Hook, fn func(ctx context.Context) error, stage string) error {
+	if fn == nil {
+		return nil
+	}
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if re := recover(); re != nil {
+				done <- fmt.Errorf("panic: %v", re)
+			}
+		}()
+		done <- fn(ctx)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("hook=%q %s: %w", h.Name, stage, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("hook=%q %s: %w", h.Name, stage, ctx.Err())
+	}
+}
+
+// 为了在编译期即确保实现了接口
+var _ Registry = (*registry)(nil)
@@ -0,0 +1,195 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 11:40:08
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 11:40:08
+ * @Description: lifecycle unit test
+ */
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOrder(t *testing.T) {
+	ctx := context.Background()
+	var calls []string
+	hook := func(name string, deps ...string) Hook {
+		return Hook{
+			Name:      name,
+			DependsOn: deps,
+			OnStart: func(ctx context.Context) error {
+				calls = append(calls, "start "+name)
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				calls = append(calls, "stop "+name)
+				return nil
+			},
+		}
+	}
+	r := New()
+	for _, h := range []Hook{hook("web", "mysql", "redis"), hook("mysql"), hook("redis", "mysql")} {
+		if err := r.Register(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Register(hook("mysql")); err == nil {
+		t.Error("register duplicated hook success")
+	}
+	if err := r.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"start mysql", "start redis", "start web", "stop web", "stop redis", "stop mysql"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v", calls)
+	}
+	// 重复Stop不会再次执行
+	if err := r.Stop(ctx); err != nil || len(calls) != len(want) {
+		t.Errorf("stop twice, err=%v calls=%v", err, calls)
+	}
+}
+
+func TestStartFailed(t *testing.T) {
+	ctx := context.Background()
+	errBoom := errors.New("boom")
+	var stopped []string
+	r := New()
+	_ = r.Register(Hook{
+		Name: "a",
+		OnStop: func(ctx context.Context) error {
+			stopped = append(stopped, "a")
+			return errors.New("close a")
+		},
+	})
+	_ = r.Register(Hook{
+		Name: "b",
+		OnStart: func(ctx context.Context) error {
+			return errBoom
+		},
+		OnStop: func(ctx context.Context) error {
+			stopped = append(stopped, "b")
+			return nil
+		},
+	})
+	_ = r.Register(Hook{
+		Name:    "c",
+		Timeout: 10 * time.Millisecond,
+		OnStart: func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		},
+	})
+	err := r.Start(ctx)
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("err = %v", err)
+	}
+	// b 启动失败, 只有 a 需要关闭, 且 a 关闭的错误也要返回
+	if !reflect.DeepEqual(stopped, []string{"a"}) {
+		t.Errorf("stopped = %v", stopped)
+	}
+	if err.Error() == errBoom.Error() {
+		t.Errorf("stop error not aggregated: %v", err)
+	}
+}
+
+func TestTimeoutAndCycle(t *testing.T) {
+	ctx := context.Background()
+	r := New()
+	_ = r.Register(Hook{
+		Name:    "slow",
+		Timeout: 10 * time.Millisecond,
+		OnStart: func(ctx context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		},
+	})
+	if err := r.Start(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v", err)
+	}
+
+	r = New()
+	_ = r.Register(Hook{Name: "a", DependsOn: []string{"b"}})
+	_ = r.Register(Hook{Name: "b", DependsOn: []string{"a"}})
+	if _, err := r.Names(); err == nil {
+		t.Error("cycle not detected")
+	}
+	r = New()
+	_ = r.Register(Hook{Name: "a", DependsOn: []string{"not exist"}})
+	if err := r.Start(ctx); err == nil {
+		t.Error("missing dependency not detected")
+	}
+}
+
+func TestRegisterInHook(t *testing.T) {
+	ctx := context.Background()
+	r := New()
+	var startErr error
+	var stopNames []string
+	_ = r.Register(Hook{
+		Name: "a",
+		OnStart: func(ctx context.Context) error {
+			startErr = r.Register(Hook{Name: "late"})
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			stopNames, _ = r.Names()
+			return nil
+		},
+	})
+	done := make(chan error, 1)
+	go func() {
+		if err := r.Start(ctx); err != nil {
+			done <- err
+			return
+		}
+		done <- r.Stop(ctx)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("deadlock")
+	}
+	if !errors.Is(startErr, ErrStarted) || len(stopNames) != 1 {
+		t.Errorf("startErr=%v stopNames=%v", startErr, stopNames)
+	}
+}
+
+func TestClone(t *testing.T) {
+	ctx := context.Background()
+	r := New()
+	var stopped int
+	_ = r.Register(Hook{Name: "a", OnStop: func(ctx context.Context) error {
+		stopped++
+		return nil
+	}})
+	if err := r.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// 复制的实例未启动, 可以继续注册
+	r1 := r.Clone()
+	if err := r1.Register(Hook{Name: "b", DependsOn: []string{"a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if names, _ := r.Names(); !reflect.DeepEqual(names, []string{"a"}) {
+		t.Fatalf("original changed: %v", names)
+	}
+	if err := r1.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	_ = r1.Stop(ctx)
+	_ = r.Stop(ctx)
+	if stopped != 2 {
+		t.Fatalf("stopped=%d", stopped)
+	}
+}
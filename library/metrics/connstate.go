@@ -0,0 +1,80 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 18:44:51
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 18:44:51
+ * @Description: http连接状态的metrics
+ */
+package metrics
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// Namespace 框架指标的命名空间
+	Namespace = "simple_boot"
+	// http服务的指标
+	subsystemHTTPServer = "http_server"
+)
+
+var (
+	// Connections 当前各状态的连接数, 即 simple_boot_http_server_connections
+	Connections = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: subsystemHTTPServer,
+			Name:      "connections",
+			Help:      "Number of current connections by listener and state.",
+		},
+		[]string{"listener", "state"},
+	)
+	// ConnectionsTotal 累计接受的连接数, 即 simple_boot_http_server_connections_total
+	ConnectionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: subsystemHTTPServer,
+			Name:      "connections_total",
+			Help:      "Number of accepted connections by listener.",
+		},
+		[]string{"listener"},
+	)
+)
+
+// RegisterConnState 将连接数的指标注册到reg, 已经注册过时忽略
+func RegisterConnState(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{Connections, ConnectionsTotal} {
+		if err := reg.Register(c); err != nil {
+			var are prometheus.AlreadyRegisteredError
+			if !errors.As(err, &are) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ConnState 返回 http.Server.ConnState 使用的回调, 统计listener上各状态的连接数
+// 连接关闭或被接管(如websocket)后不再统计
+func ConnState(listener string) func(net.Conn, http.ConnState) {
+	var states sync.Map // net.Conn -> http.ConnState
+	return func(conn net.Conn, state http.ConnState) {
+		if prev, ok := states.Load(conn); ok {
+			Connections.WithLabelValues(listener, prev.(http.ConnState).String()).Dec()
+		}
+		switch state {
+		case http.StateClosed, http.StateHijacked:
+			states.Delete(conn)
+			return
+		case http.StateNew:
+			ConnectionsTotal.WithLabelValues(listener).Inc()
+		}
+		states.Store(conn, state)
+		Connections.WithLabelValues(listener, state.String()).Inc()
+	}
+}
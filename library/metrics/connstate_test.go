@@ -0,0 +1,52 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-19 12:05:37
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-19 12:05:37
+ * @Description: 连接状态metrics测试
+ */
+package metrics
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestConnState(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	if err := RegisterConnState(reg); err != nil {
+		t.Fatal(err)
+	}
+	// 重复注册忽略
+	if err := RegisterConnState(reg); err != nil {
+		t.Fatalf("register again: %v", err)
+	}
+
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+	fn := ConnState("test_conn_state")
+	fn(server, http.StateNew)
+	fn(server, http.StateActive)
+
+	handler := gin.New()
+	handler.GET("/metrics", HandlerFor(reg))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+	for _, want := range []string{
+		`simple_boot_http_server_connections{listener="test_conn_state",state="active"} 1`,
+		`simple_boot_http_server_connections{listener="test_conn_state",state="new"} 0`,
+		`simple_boot_http_server_connections_total{listener="test_conn_state"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing %s in:\n%s", want, body)
+		}
+	}
+}
@@ -29,9 +29,21 @@ func init() {
 	prometheus.MustRegister(TotalRequests)
 }
 
+var (
+	// Registerer bootstrap 注册框架指标(如连接数)的位置, 需要在 bootstrap.Setup 之前修改
+	Registerer prometheus.Registerer = prometheus.DefaultRegisterer
+	// Gatherer admin监听的 /metrics 输出的指标
+	Gatherer prometheus.Gatherer = prometheus.DefaultGatherer
+)
+
 // prometheusHandler 返回一个处理程序，该处理程序调用 promhttp 包中的 HandlerFor
 func PrometheusHandler() gin.HandlerFunc {
-	h := promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{})
+	return HandlerFor(prometheus.DefaultGatherer)
+}
+
+// HandlerFor 输出g中的指标
+func HandlerFor(g prometheus.Gatherer) gin.HandlerFunc {
+	h := promhttp.HandlerFor(g, promhttp.HandlerOpts{})
 	return func(c *gin.Context) {
 		h.ServeHTTP(c.Writer, c.Request)
 	}
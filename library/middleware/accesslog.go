@@ -0,0 +1,118 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 16:12:40
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 16:48:02
+ * @Description: 访问日志
+ */
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liziwei01/simple-boot/library/trace"
+)
+
+const (
+	// AccessLogFormatText 空格分隔的文本格式
+	AccessLogFormatText = "text"
+	// AccessLogFormatJSON 每行一个json
+	AccessLogFormatJSON = "json"
+)
+
+const accessLogTimeFormat = "2006-01-02 15:04:05.000"
+
+// accessLogEntry 一行访问日志
+type accessLogEntry struct {
+	Time      string  `json:"time"`
+	ClientIP  string  `json:"client_ip"`
+	Method    string  `json:"method"`
+	URI       string  `json:"uri"`
+	Route     string  `json:"route"`
+	Status    int     `json:"status"`
+	Bytes     int     `json:"bytes"`
+	Latency   float64 `json:"latency_ms"`
+	RequestID string  `json:"request_id"`
+	UserAgent string  `json:"user_agent"`
+}
+
+// AccessLog 每个请求处理完成后输出一行日志到w, 格式为 text 或 json
+// 每行使用独立的[]byte, 可以直接配合 writer.NewAsync 使用
+func AccessLog(w io.Writer, format string) (gin.HandlerFunc, error) {
+	var encode func(e *accessLogEntry) []byte
+	switch format {
+	case "", AccessLogFormatText:
+		encode = encodeAccessLogText
+	case AccessLogFormatJSON:
+		encode = encodeAccessLogJSON
+	default:
+		return nil, fmt.Errorf("access log format %q not supported", format)
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		requestID := GetRequestID(c)
+		if id := c.GetHeader(RequestIDHeader); requestID == "" && trace.ValidRequestID(id) {
+			requestID = id
+		}
+		size := c.Writer.Size()
+		if size < 0 {
+			size = 0
+		}
+		_, _ = w.Write(encode(&accessLogEntry{
+			Time:      start.Format(accessLogTimeFormat),
+			ClientIP:  c.ClientIP(),
+			Method:    c.Request.Method,
+			URI:       c.Request.URL.RequestURI(),
+			Route:     c.FullPath(),
+			Status:    c.Writer.Status(),
+			Bytes:     size,
+			Latency:   float64(time.Since(start).Microseconds()) / 1000,
+			RequestID: requestID,
+			UserAgent: c.Request.UserAgent(),
+		}))
+	}, nil
+}
+
+// encodeAccessLogText 字符串字段使用%q输出, 避免日志注入
+func encodeAccessLogText(e *accessLogEntry) []byte {
+	b := make([]byte, 0, 256)
+	b = append(b, e.Time...)
+	b = append(b, ' ')
+	b = append(b, e.ClientIP...)
+	b = append(b, ' ')
+	b = append(b, e.Method...)
+	b = append(b, ' ')
+	b = strconv.AppendQuote(b, e.URI)
+	b = append(b, ' ')
+	b = strconv.AppendInt(b, int64(e.Status), 10)
+	b = append(b, ' ')
+	b = strconv.AppendInt(b, int64(e.Bytes), 10)
+	b = append(b, ' ')
+	b = strconv.AppendFloat(b, e.Latency, 'f', 3, 64)
+	b = append(b, "ms "...)
+	b = append(b, dash(e.RequestID)...)
+	b = append(b, ' ')
+	b = strconv.AppendQuote(b, e.Route)
+	b = append(b, ' ')
+	b = strconv.AppendQuote(b, e.UserAgent)
+	return append(b, '\n')
+}
+
+func encodeAccessLogJSON(e *accessLogEntry) []byte {
+	b, _ := json.Marshal(e)
+	return append(b, '\n')
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,31 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 16:12:40
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 16:12:40
+ * @Description: 请求体大小限制
+ */
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liziwei01/simple-boot/library/errs"
+	"github.com/liziwei01/simple-boot/library/resp"
+)
+
+// BodyLimit 请求体超过maxBytes时返回413
+// 带Content-Length的请求直接拒绝, chunked的请求在读取超过限制时返回错误
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > maxBytes {
+			resp.Error(c, errs.ErrRequestEntityTooLarge)
+			return
+		}
+		if c.Request.Body != nil && c.Request.Body != http.NoBody {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		}
+		c.Next()
+	}
+}
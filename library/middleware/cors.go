@@ -0,0 +1,120 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 16:12:40
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 16:12:40
+ * @Description: 跨域
+ */
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSConfig 跨域配置
+type CORSConfig struct {
+	// AllowOrigins 允许的来源, 如 https://example.com、https://*.example.com, * 表示所有
+	AllowOrigins []string
+	// AllowMethods 默认 GET POST PUT PATCH DELETE HEAD OPTIONS
+	AllowMethods []string
+	// AllowHeaders 为空时使用预检请求中的 Access-Control-Request-Headers
+	AllowHeaders []string
+	// ExposeHeaders 允许浏览器读取的响应header
+	ExposeHeaders []string
+	// AllowCredentials 是否允许携带cookie, 此时不会返回 *
+	AllowCredentials bool
+	// MaxAge 预检请求结果的缓存时间
+	MaxAge int // s
+}
+
+var defaultCORSMethods = []string{
+	http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch,
+	http.MethodDelete, http.MethodHead, http.MethodOptions,
+}
+
+// CORS 跨域, 来源不被允许时不添加跨域header, 由浏览器拦截
+// 预检请求直接返回204
+func CORS(conf CORSConfig) gin.HandlerFunc {
+	methods := conf.AllowMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(conf.AllowHeaders, ", ")
+	exposeHeaders := strings.Join(conf.ExposeHeaders, ", ")
+	maxAge := ""
+	if conf.MaxAge > 0 {
+		maxAge = strconv.Itoa(conf.MaxAge)
+	}
+	allowAll := false
+	for _, origin := range conf.AllowOrigins {
+		if origin == "*" {
+			allowAll = true
+		}
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+		h := c.Writer.Header()
+		h.Add("Vary", "Origin")
+		if !allowAll && !matchOrigin(conf.AllowOrigins, origin) {
+			c.Next()
+			return
+		}
+		if allowAll && !conf.AllowCredentials {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+		}
+		if conf.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		if !preflight {
+			if exposeHeaders != "" {
+				h.Set("Access-Control-Expose-Headers", exposeHeaders)
+			}
+			c.Next()
+			return
+		}
+		h.Add("Vary", "Access-Control-Request-Method")
+		h.Add("Vary", "Access-Control-Request-Headers")
+		h.Set("Access-Control-Allow-Methods", allowMethods)
+		if allowHeaders != "" {
+			h.Set("Access-Control-Allow-Headers", allowHeaders)
+		} else if reqHeaders := c.GetHeader("Access-Control-Request-Headers"); reqHeaders != "" {
+			h.Set("Access-Control-Allow-Headers", reqHeaders)
+		}
+		if maxAge != "" {
+			h.Set("Access-Control-Max-Age", maxAge)
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// matchOrigin 支持一级通配符, 如 https://*.example.com 匹配 https://a.example.com, 不匹配 https://a.b.example.com
+func matchOrigin(allowOrigins []string, origin string) bool {
+	for _, allow := range allowOrigins {
+		if strings.EqualFold(allow, origin) {
+			return true
+		}
+		prefix, suffix, found := strings.Cut(allow, "*")
+		if !found || len(origin) <= len(prefix)+len(suffix) {
+			continue
+		}
+		if strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) &&
+			!strings.ContainsAny(origin[len(prefix):len(origin)-len(suffix)], "/.:") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 16:12:40
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 16:12:40
+ * @Description: gzip压缩响应
+ */
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GzipConfig gzip配置
+type GzipConfig struct {
+	// Level 压缩等级 1-9, 默认为 gzip.DefaultCompression
+	Level int
+	// ExcludedPaths 不压缩的路由前缀, 如 /metrics
+	ExcludedPaths []string
+}
+
+// Gzip 客户端支持gzip时压缩响应
+// 已经设置了Content-Encoding的响应(如已压缩的文件)不再压缩
+func Gzip(conf GzipConfig) (gin.HandlerFunc, error) {
+	level := conf.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	// 提前校验level, 避免请求时出错
+	if _, err := gzip.NewWriterLevel(io.Discard, level); err != nil {
+		return nil, err
+	}
+	pool := &sync.Pool{
+		New: func() any {
+			w, _ := gzip.NewWriterLevel(io.Discard, level)
+			return w
+		},
+	}
+
+	return func(c *gin.Context) {
+		if !acceptGzip(c.Request) || c.Request.Method == http.MethodHead || excludedPath(conf.ExcludedPaths, c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+		gw := &gzipWriter{ResponseWriter: c.Writer, pool: pool}
+		c.Writer = gw
+		defer func() {
+			gw.close()
+			c.Writer = gw.ResponseWriter
+		}()
+		c.Next()
+	}, nil
+}
+
+// gzipWriter 第一次写入时决定是否压缩
+type gzipWriter struct {
+	gin.ResponseWriter
+	pool *sync.Pool
+	gz   *gzip.Writer
+	// 是否已经决定了是否压缩
+	decided bool
+}
+
+func (w *gzipWriter) WriteHeader(code int) {
+	w.decide(code)
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *gzipWriter) WriteHeaderNow() {
+	w.decide(w.Status())
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	w.decide(w.Status())
+	if w.gz == nil {
+		return w.ResponseWriter.Write(data)
+	}
+	w.ResponseWriter.WriteHeaderNow()
+	return w.gz.Write(data)
+}
+
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *gzipWriter) Flush() {
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide 没有body的状态码及已编码的响应不压缩
+func (w *gzipWriter) decide(code int) {
+	if w.decided {
+		return
+	}
+	w.decided = true
+	h := w.Header()
+	h.Add("Vary", "Accept-Encoding")
+	if code < http.StatusOK || code == http.StatusNoContent || code == http.StatusNotModified || h.Get("Content-Encoding") != "" {
+		return
+	}
+	h.Set("Content-Encoding", "gzip")
+	h.Del("Content-Length")
+	w.gz = w.pool.Get().(*gzip.Writer)
+	w.gz.Reset(w.ResponseWriter)
+}
+
+func (w *gzipWriter) close() {
+	if w.gz == nil {
+		return
+	}
+	_ = w.gz.Close()
+	w.gz.Reset(io.Discard)
+	w.pool.Put(w.gz)
+	w.gz = nil
+}
+
+func acceptGzip(req *http.Request) bool {
+	for _, v := range strings.Split(req.Header.Get("Accept-Encoding"), ",") {
+		name, _, _ := strings.Cut(strings.TrimSpace(v), ";")
+		if strings.EqualFold(name, "gzip") {
+			return true
+		}
+	}
+	return false
+}
+
+func excludedPath(prefixes []string, path string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,208 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 16:12:40
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 16:12:40
+ * @Description: 中间件测试
+ */
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liziwei01/simple-boot/library/jwtoken"
+)
+
+func newEngine(handlers ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	e := gin.New()
+	e.Use(handlers...)
+	e.GET("/ok", func(c *gin.Context) {
+		c.String(http.StatusOK, strings.Repeat("hello ", 100))
+	})
+	e.POST("/echo", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.String(http.StatusRequestEntityTooLarge, err.Error())
+			return
+		}
+		c.String(http.StatusOK, string(body))
+	})
+	e.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+	return e
+}
+
+func TestRecoveryAndRequestID(t *testing.T) {
+	e := newEngine(RequestID(), Recovery(io.Discard))
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set(RequestIDHeader, "abc-123")
+	e.ServeHTTP(w, req)
+	if w.Code != http.StatusInternalServerError || !strings.Contains(w.Body.String(), `"code":500`) {
+		t.Fatalf("code=%d body=%s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get(RequestIDHeader); got != "abc-123" {
+		t.Fatalf("request id=%q", got)
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/ok", nil)
+	req.Header.Set(RequestIDHeader, "bad\nid")
+	e.ServeHTTP(w, req)
+	if got := w.Header().Get(RequestIDHeader); len(got) != 32 {
+		t.Fatalf("request id=%q, want generated", got)
+	}
+}
+
+func TestCORS(t *testing.T) {
+	e := newEngine(CORS(CORSConfig{AllowOrigins: []string{"https://*.example.com"}, MaxAge: 600}))
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodOptions, "/ok", nil)
+	req.Header.Set("Origin", "https://a.example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	e.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != "https://a.example.com" {
+		t.Fatalf("code=%d header=%v", w.Code, w.Header())
+	}
+
+	// 通配符只匹配一级
+	for _, origin := range []string{"https://evil.com/.example.com", "https://a.b.example.com", "https://example.com"} {
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "/ok", nil)
+		req.Header.Set("Origin", origin)
+		e.ServeHTTP(w, req)
+		if w.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Fatalf("origin %q should not be allowed, header=%v", origin, w.Header())
+		}
+	}
+}
+
+func TestGzipAndBodyLimit(t *testing.T) {
+	gz, err := Gzip(GzipConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := newEngine(gz, BodyLimit(8))
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	e.ServeHTTP(w, req)
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("header=%v", w.Header())
+	}
+	r, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(r)
+	if string(body) != strings.Repeat("hello ", 100) {
+		t.Fatalf("body=%q", body)
+	}
+
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("0123456789")))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("code=%d", w.Code)
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	if _, err := AccessLog(io.Discard, "xml"); err == nil {
+		t.Fatal("unknown format should fail")
+	}
+	var buf bytes.Buffer
+	al, err := AccessLog(&buf, AccessLogFormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := newEngine(RequestID(), al)
+	req := httptest.NewRequest(http.MethodGet, "/ok?a=1", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+	var entry accessLogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("line=%q err=%v", buf.String(), err)
+	}
+	if entry.Route != "/ok" || entry.URI != "/ok?a=1" || entry.Status != http.StatusOK || entry.Bytes != 600 || entry.RequestID != "req-1" {
+		t.Fatalf("entry=%+v", entry)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	rl, err := RateLimit(RateLimitConfig{Policies: []RateLimitPolicy{
+		{Routes: []string{"/ok"}, Key: RateLimitKeyJWT, Rate: 0.5, Burst: 1},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := newEngine(rl)
+	token, _ := jwtoken.GenToken("alice")
+	do := func(path string, token string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		e.ServeHTTP(w, req)
+		return w
+	}
+	if w := do("/ok", token); w.Code != http.StatusOK {
+		t.Fatalf("code=%d", w.Code)
+	}
+	w := do("/ok", token)
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "2" {
+		t.Fatalf("code=%d header=%v", w.Code, w.Header())
+	}
+	// 没有token时按ip限流, 与jwt用户互不影响
+	if w := do("/ok", ""); w.Code != http.StatusOK {
+		t.Fatalf("code=%d", w.Code)
+	}
+	// 没有用户信息的token同样按ip限流, 不共用一个桶
+	anonymous, _ := jwtoken.GenToken("")
+	if w := do("/ok", anonymous); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("token without subject should use the ip bucket, code=%d", w.Code)
+	}
+	// 其他路由不限流
+	if w := do("/ok?", token); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("query should not bypass the limit, code=%d", w.Code)
+	}
+	if w := do("/none", token); w.Code == http.StatusTooManyRequests {
+		t.Fatalf("code=%d", w.Code)
+	}
+
+	// 被后面的策略拒绝时, 前面的策略不消耗额度
+	rl, err = RateLimit(RateLimitConfig{Policies: []RateLimitPolicy{
+		{Routes: []string{"/ok"}, Rate: 0.5, Burst: 2},
+		{Routes: []string{"/ok"}, Key: RateLimitKeyHeaderPrefix + "X-App-Key", Rate: 0.5, Burst: 1},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e = newEngine(rl)
+	for i, want := range []struct {
+		app  string
+		code int
+	}{{"a", http.StatusOK}, {"a", http.StatusTooManyRequests}, {"b", http.StatusOK}, {"c", http.StatusTooManyRequests}} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+		req.Header.Set("X-App-Key", want.app)
+		e.ServeHTTP(w, req)
+		if w.Code != want.code {
+			t.Fatalf("request %d app=%s: code=%d, want %d", i, want.app, w.Code, want.code)
+		}
+	}
+
+	if _, err := RateLimit(RateLimitConfig{Policies: []RateLimitPolicy{{Key: "cookie", Rate: 1}}}); err == nil {
+		t.Fatal("unknown key should fail")
+	}
+}
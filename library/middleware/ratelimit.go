@@ -0,0 +1,177 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 19:06:27
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 19:06:27
+ * @Description: 按路由配置的限流
+ */
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liziwei01/simple-boot/library/errs"
+	"github.com/liziwei01/simple-boot/library/jwtoken"
+	"github.com/liziwei01/simple-boot/library/ratelimit"
+	"github.com/liziwei01/simple-boot/library/resp"
+)
+
+const (
+	// RateLimitKeyIP 按客户端IP限流
+	RateLimitKeyIP = "ip"
+	// RateLimitKeyHeaderPrefix 按header的值限流, 如 header:X-App-Key
+	RateLimitKeyHeaderPrefix = "header:"
+	// RateLimitKeyJWT 按jwt中的用户限流, 即 Authorization: Bearer <token>
+	RateLimitKeyJWT = "jwt"
+)
+
+// RateLimitConfig 限流配置, 请求需要满足所有匹配的策略
+//
+//	[[HTTPServer.Middlewares.RateLimit.Policies]]
+//	Routes = ["/api/login"]
+//	Key = "ip"
+//	Rate = 1
+//	Burst = 5
+type RateLimitConfig struct {
+	Policies []RateLimitPolicy
+	// TTL 限流状态多久没有访问后被清理, 默认10分钟
+	TTL int // ms
+}
+
+// RateLimitPolicy 一条限流策略
+type RateLimitPolicy struct {
+	// Routes 生效的路由模板, 如 /api/user/:id, 以*结尾时按前缀匹配, 为空时对所有请求生效
+	Routes []string
+	// Methods 生效的请求方法, 为空时对所有方法生效
+	Methods []string
+	// Key 限流的维度: ip、header:<Name>、jwt, 默认ip
+	// header或jwt取不到值(包括jwt中没有Subject及Username)时按ip限流
+	Key string
+	// Rate 每秒允许的请求数
+	Rate float64
+	// Burst 允许的突发请求数, 默认为Rate向上取整
+	Burst int
+}
+
+// rateLimiter 一条策略及其令牌桶
+type rateLimiter struct {
+	RateLimitPolicy
+	limiter ratelimit.Limiter
+}
+
+// RateLimit 超过限制时返回429及Retry-After
+func RateLimit(conf RateLimitConfig) (gin.HandlerFunc, error) {
+	ttl := time.Millisecond * time.Duration(conf.TTL)
+	limiters := make([]*rateLimiter, 0, len(conf.Policies))
+	for i, p := range conf.Policies {
+		if p.Rate <= 0 {
+			return nil, fmt.Errorf("rate limit policy %d: Rate should be greater than 0", i)
+		}
+		if p.Key == "" {
+			p.Key = RateLimitKeyIP
+		}
+		if p.Key != RateLimitKeyIP && p.Key != RateLimitKeyJWT && !strings.HasPrefix(p.Key, RateLimitKeyHeaderPrefix) {
+			return nil, fmt.Errorf("rate limit policy %d: Key %q not supported", i, p.Key)
+		}
+		burst := p.Burst
+		if burst <= 0 {
+			burst = int(math.Ceil(p.Rate))
+		}
+		limiters = append(limiters, &rateLimiter{
+			RateLimitPolicy: p,
+			limiter:         ratelimit.NewTokenBucket(p.Rate, burst, ttl),
+		})
+	}
+
+	return func(c *gin.Context) {
+		var (
+			matched    []*rateLimiter
+			keys       []string
+			retryAfter time.Duration
+		)
+		// 先检查所有匹配的策略, 都满足时才消耗, 被拒绝的请求不占用其他策略的额度
+		for _, l := range limiters {
+			if !l.match(c) {
+				continue
+			}
+			key := l.key(c)
+			if ok, wait := l.limiter.Check(key); !ok && wait > retryAfter {
+				retryAfter = wait
+			}
+			matched, keys = append(matched, l), append(keys, key)
+		}
+		if retryAfter == 0 {
+			// 检查后额度可能被并发的请求用完
+			for i, l := range matched {
+				if ok, wait := l.limiter.Allow(keys[i]); !ok && wait > retryAfter {
+					retryAfter = wait
+				}
+			}
+		}
+		if retryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			resp.Error(c, errs.ErrTooManyRequests)
+			return
+		}
+		c.Next()
+	}, nil
+}
+
+// match 请求是否匹配策略
+func (l *rateLimiter) match(c *gin.Context) bool {
+	if len(l.Methods) > 0 && !containsFold(l.Methods, c.Request.Method) {
+		return false
+	}
+	if len(l.Routes) == 0 {
+		return true
+	}
+	route := c.FullPath()
+	for _, r := range l.Routes {
+		if prefix, ok := strings.CutSuffix(r, "*"); ok && strings.HasPrefix(route, prefix) {
+			return true
+		}
+		if r == route {
+			return true
+		}
+	}
+	return false
+}
+
+// key 限流的key, 不同的维度加上前缀避免冲突
+func (l *rateLimiter) key(c *gin.Context) string {
+	switch {
+	case l.Key == RateLimitKeyJWT:
+		auth := c.GetHeader("Authorization")
+		if token, ok := strings.CutPrefix(auth, "Bearer "); ok {
+			if claims, err := jwtoken.ParseToken(token); err == nil {
+				subject := claims.Subject
+				if subject == "" {
+					subject = claims.Username
+				}
+				// 没有用户信息的token不能共用一个桶
+				if subject != "" {
+					return "jwt:" + subject
+				}
+			}
+		}
+	case strings.HasPrefix(l.Key, RateLimitKeyHeaderPrefix):
+		if v := c.GetHeader(strings.TrimPrefix(l.Key, RateLimitKeyHeaderPrefix)); v != "" {
+			return "header:" + v
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
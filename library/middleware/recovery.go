@@ -0,0 +1,66 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 16:12:40
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 16:12:40
+ * @Description: panic恢复, 返回json格式的错误
+ */
+package middleware
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liziwei01/simple-boot/library/errs"
+	"github.com/liziwei01/simple-boot/library/resp"
+)
+
+// Recovery 捕获panic, 打印堆栈到w, 返回500及json格式的错误, 见 resp.Error
+// 客户端断开连接导致的panic只打印日志, 不再写响应
+func Recovery(w io.Writer) gin.HandlerFunc {
+	if w == nil {
+		w = os.Stderr
+	}
+	logger := log.New(w, "[Recovery] ", log.LstdFlags)
+	return func(c *gin.Context) {
+		defer func() {
+			re := recover()
+			if re == nil {
+				return
+			}
+			// 由net/http处理, 直接断开连接
+			if re == http.ErrAbortHandler {
+				panic(re)
+			}
+			if err, ok := re.(error); ok && brokenPipe(err) {
+				logger.Printf("%s %s connection broken: %v\n", c.Request.Method, c.Request.URL.Path, err)
+				_ = c.Error(err)
+				c.Abort()
+				return
+			}
+			logger.Printf("%s %s request_id=%s panic: %v\n%s", c.Request.Method, c.Request.URL.Path, GetRequestID(c), re, debug.Stack())
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			resp.Error(c, errs.ErrInternal)
+		}()
+		c.Next()
+	}
+}
+
+// brokenPipe 客户端断开连接
+func brokenPipe(err error) bool {
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	return errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)
+}
@@ -0,0 +1,32 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 16:12:40
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 17:05:31
+ * @Description: 请求id及trace context
+ */
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/liziwei01/simple-boot/library/trace"
+)
+
+// RequestIDHeader 请求id的header
+const RequestIDHeader = trace.RequestIDHeader
+
+// RequestID 优先使用上游传入的 X-Request-Id 及 traceparent, 没有时生成一个新的调用链
+// 追踪信息存入 c.Request.Context(), 传给mysql、redis等组件的ctx会自动带上, 并写入响应的header
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t := trace.FromHeader(c.Request.Header)
+		c.Request = c.Request.WithContext(trace.NewContext(c.Request.Context(), t))
+		t.Inject(c.Writer.Header())
+		c.Next()
+	}
+}
+
+// GetRequestID 获取当前请求的id, 未启用 RequestID 时返回空
+func GetRequestID(c *gin.Context) string {
+	return trace.RequestID(c.Request.Context())
+}
@@ -0,0 +1,73 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 16:12:40
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 16:12:40
+ * @Description: 安全相关的响应header
+ */
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SecurityHeadersConfig 安全header配置, 字段为空时使用默认值, 为 "-" 时不输出
+type SecurityHeadersConfig struct {
+	// FrameOptions X-Frame-Options, 默认 DENY
+	FrameOptions string
+	// ContentTypeOptions X-Content-Type-Options, 默认 nosniff
+	ContentTypeOptions string
+	// ReferrerPolicy Referrer-Policy, 默认 strict-origin-when-cross-origin
+	ReferrerPolicy string
+	// ContentSecurityPolicy Content-Security-Policy, 默认不输出
+	ContentSecurityPolicy string
+	// HSTSMaxAge https请求输出 Strict-Transport-Security 的max-age, 默认1年, 小于0不输出
+	HSTSMaxAge int // s
+	// HSTSIncludeSubdomains 是否包含子域名
+	HSTSIncludeSubdomains bool
+}
+
+const defaultHSTSMaxAge = 365 * 24 * 3600
+
+// SecurityHeaders 为所有响应添加安全相关的header
+func SecurityHeaders(conf SecurityHeadersConfig) gin.HandlerFunc {
+	headers := map[string]string{}
+	addHeader := func(name string, value string, defaultValue string) {
+		if value == "" {
+			value = defaultValue
+		}
+		if value != "" && value != "-" {
+			headers[name] = value
+		}
+	}
+	addHeader("X-Frame-Options", conf.FrameOptions, "DENY")
+	addHeader("X-Content-Type-Options", conf.ContentTypeOptions, "nosniff")
+	addHeader("Referrer-Policy", conf.ReferrerPolicy, "strict-origin-when-cross-origin")
+	addHeader("Content-Security-Policy", conf.ContentSecurityPolicy, "")
+
+	hsts := ""
+	if conf.HSTSMaxAge >= 0 {
+		maxAge := conf.HSTSMaxAge
+		if maxAge == 0 {
+			maxAge = defaultHSTSMaxAge
+		}
+		hsts = fmt.Sprintf("max-age=%d", maxAge)
+		if conf.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+
+	return func(c *gin.Context) {
+		h := c.Writer.Header()
+		for name, value := range headers {
+			h.Set(name, value)
+		}
+		// 只有https的响应才能设置HSTS
+		if hsts != "" && c.Request.TLS != nil {
+			h.Set("Strict-Transport-Security", hsts)
+		}
+		c.Next()
+	}
+}
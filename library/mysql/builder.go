@@ -9,8 +9,10 @@ package mysql
 
 import (
 	"context"
+	"unicode/utf8"
 
 	"github.com/didi/gendry/builder"
+	"github.com/liziwei01/simple-boot/library/trace"
 )
 
 const (
@@ -200,12 +202,21 @@ func (b *RawBuilder) CompileContext(ctx context.Context, c Client) (string, []in
 	return b.sql, b.args, nil
 }
 
+// log 打印带占位符的sql及参数的个数, 带上ctx中的请求id
+// 参数中可能有密码、token等敏感信息, 不打印参数的值
+// SQLLogLen 为0时不打印, 为-1时打印完整的sql, 大于0时按字符边界截断到该长度以内
 func log(ctx context.Context, c Client, cond string, values []interface{}) {
-	// query := fmt.Sprintf(cond, values...)
-	// if c.sqlloglen() != -1 {
-	// 	query = query[0:c.sqlloglen()]
-	// }
-	// if len(query) != 0 {
-	// 	logit.Logger.Info("[MySQL] [requestID]=%d, [query]=%s", ctx.Value("requestID"), query)
-	// }
+	logLen := c.sqlloglen()
+	if logLen == 0 || cond == "" {
+		return
+	}
+	query := cond
+	if logLen > 0 && len(query) > logLen {
+		n := logLen
+		for n > 0 && !utf8.RuneStart(query[n]) {
+			n--
+		}
+		query = query[:n] + "..."
+	}
+	trace.Logf(ctx, "[MySQL] service=%s query=%s args=%d", c.name(), query, len(values))
 }
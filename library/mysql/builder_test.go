@@ -0,0 +1,48 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-19 11:05:37
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-19 11:05:37
+ * @Description: sql日志测试
+ */
+package mysql
+
+import (
+	"bytes"
+	"context"
+	stdlog "log"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/liziwei01/simple-boot/library/trace"
+)
+
+// logClient 只提供打印日志需要的配置
+type logClient struct {
+	Client
+	logLen int
+}
+
+func (c logClient) name() string   { return "db" }
+func (c logClient) sqlloglen() int { return c.logLen }
+
+func TestLog(t *testing.T) {
+	var logs bytes.Buffer
+	logger := trace.Logger
+	trace.Logger = stdlog.New(&logs, "", 0)
+	t.Cleanup(func() { trace.Logger = logger })
+
+	query := "SELECT * FROM user WHERE name=? AND password=? -- 用户"
+	log(context.Background(), logClient{logLen: -1}, query, []interface{}{"bob", "hunter2"})
+	if got := logs.String(); strings.Contains(got, "hunter2") || !strings.Contains(got, query+" args=2") {
+		t.Fatalf("log=%q", got)
+	}
+
+	// 截断时不切开多字节字符
+	logs.Reset()
+	log(context.Background(), logClient{logLen: len(query) - 4}, query, nil)
+	if got := logs.String(); !utf8.ValidString(got) || !strings.Contains(got, "-- ...") {
+		t.Fatalf("log=%q", got)
+	}
+}
@@ -60,6 +60,13 @@ type Client interface {
 	sqlloglen() int
 }
 
+// pool 持有连接池的客户端实现
+// SetClient 注入的客户端(如测试中的假客户端)可以不实现, 此时退出时不关闭, 健康检查时不检查连接
+type pool interface {
+	close() error
+	ping(ctx context.Context) error
+}
+
 type client struct {
 	conf *Config
 	db   *sql.DB
@@ -100,6 +107,23 @@ func (c *client) open() (*sql.DB, error) {
 	return db, err
 }
 
+func (c *client) close() error {
+	mu.Lock()
+	defer mu.Unlock()
+	if c.db == nil {
+		return nil
+	}
+	err := c.db.Close()
+	c.db = nil
+	return err
+}
+
+// ping 检查连接是否可用, 用于健康检查
+func (c *client) ping(ctx context.Context) error {
+	_, err := c.connect(ctx)
+	return err
+}
+
 func New(config *Config) Client {
 	c := &client{
 		conf: config,
@@ -10,7 +10,7 @@ package mysql
 // Config 配置
 type Config struct {
 	// Service的名字, 必选
-	Name string
+	Name string `validate:"required"`
 
 	// 各种自定义的参数, 全部非必选
 	// 写数据超时
@@ -23,8 +23,8 @@ type Config struct {
 	// 资源定位: 手动配置 - 使用IP、端口
 	Resource struct {
 		Manual struct {
-			Host string
-			Port int
+			Host string `validate:"required"`
+			Port int    `validate:"min=1,max=65535"`
 		}
 	}
 
@@ -36,6 +36,7 @@ type Config struct {
 		Charset   string
 		Collation string
 		Timeout   int
+		// SQLLogLen 打印sql的长度, 0不打印, -1打印完整的sql
 		SQLLogLen int
 	}
 }
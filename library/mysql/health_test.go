@@ -0,0 +1,61 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-19 10:52:03
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-19 10:52:03
+ * @Description: 健康检查测试
+ */
+package mysql
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/liziwei01/simple-boot/library/health"
+)
+
+func TestHealthAfterClose(t *testing.T) {
+	prev := health.Default
+	health.Default = health.New()
+	defer func() { health.Default = prev }()
+
+	registerHealth("db_closed")
+	if err := closeClients(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	report := health.Default.Readiness(context.Background())
+	if report.OK() || len(report.Checks) != 1 || !strings.Contains(report.Checks[0].Error, "closed") {
+		t.Fatalf("report=%+v", report)
+	}
+	// 检查不会重新创建client
+	initMux.Lock()
+	_, has := clients["db_closed"]
+	initMux.Unlock()
+	if has {
+		t.Fatal("client re-created by the readiness check")
+	}
+}
+
+// fakeClient 不持有连接池, closeClients 时跳过
+type fakeClient struct {
+	Client
+}
+
+func TestGetClientWhileClosing(t *testing.T) {
+	restore := SetClient("db_race", &fakeClient{})
+	defer restore()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			_, _ = GetClient(context.Background(), "db_race")
+		}
+	}()
+	// 在 go test -race 下检查与 GetClient 的读是否有竞争
+	for i := 0; i < 100; i++ {
+		_ = closeClients(context.Background())
+		SetClient("db_race", &fakeClient{})
+	}
+	<-done
+}
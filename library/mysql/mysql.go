@@ -3,6 +3,8 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 
 	"github.com/didi/gendry/scanner"
 	_ "github.com/go-sql-driver/mysql"
@@ -48,13 +50,28 @@ func (dao *client) ExecRaw(ctx context.Context, sql string, args ...interface{})
 	return ExecWithBuilder(ctx, dao, builder)
 }
 
+// ErrNotRealClient builder相关的函数只能用于 GetClient 创建的客户端, SetClient 注入的假客户端没有连接池
+var ErrNotRealClient = errors.New("mysql: builder needs a client created by GetClient")
+
+// realClient 取出真实的客户端, 假客户端返回 ErrNotRealClient 而不是调用nil的未导出方法
+func realClient(c Client) (*client, error) {
+	if cli, ok := c.(*client); ok && cli != nil {
+		return cli, nil
+	}
+	return nil, fmt.Errorf("%T: %w", c, ErrNotRealClient)
+}
+
 // QueryWithBuilder 传入一个 SQLBuilder 并执行 QueryContext
-func QueryWithBuilder(ctx context.Context, client Client, builder Builder, data interface{}) error {
-	db, err := client.connect(ctx)
+func QueryWithBuilder(ctx context.Context, c Client, builder Builder, data interface{}) error {
+	cli, err := realClient(c)
 	if err != nil {
 		return err
 	}
-	cond, values, err := builder.CompileContext(ctx, client)
+	db, err := cli.connect(ctx)
+	if err != nil {
+		return err
+	}
+	cond, values, err := builder.CompileContext(ctx, cli)
 	if err != nil {
 		return err
 	}
@@ -65,28 +82,25 @@ func QueryWithBuilder(ctx context.Context, client Client, builder Builder, data
 	return scanner.ScanClose(rows, data)
 }
 
-func ExecWithBuilder(ctx context.Context, client Client, builder Builder) (sql.Result, error) {
-	db, err := client.connect(ctx)
+func ExecWithBuilder(ctx context.Context, c Client, builder Builder) (sql.Result, error) {
+	cli, err := realClient(c)
 	if err != nil {
 		return nil, err
 	}
-	cond, values, err := builder.CompileContext(ctx, client)
+	db, err := cli.connect(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return db.ExecContext(ctx, cond, values...)
-}
-
-func Execraw(ctx context.Context, client Client, builder Builder) (sql.Result, error) {
-	db, err := client.connect(ctx)
-	if err != nil {
-		return nil, err
-	}
-	cond, values, err := builder.CompileContext(ctx, client)
+	cond, values, err := builder.CompileContext(ctx, cli)
 	if err != nil {
 		return nil, err
 	}
 	return db.ExecContext(ctx, cond, values...)
 }
 
+func Execraw(ctx context.Context, c Client, builder Builder) (sql.Result, error) {
+	return ExecWithBuilder(ctx, c, builder)
+}
+
 var _ Client = (*client)(nil)
+var _ pool = (*client)(nil)
@@ -0,0 +1,277 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 20:47:19
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 20:47:19
+ * @Description: 根据路由表生成 OpenAPI 3.1 文档
+ */
+
+// Package openapi 根据 router 中声明的请求、响应类型生成 OpenAPI 3.1 文档
+// 结构体的 uri、form tag 生成路径、query参数, 其他字段按json tag生成body
+// binding tag 中的校验规则生成约束, 如 required、min、max、oneof
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/liziwei01/simple-boot/library/router"
+	"gopkg.in/yaml.v3"
+)
+
+// Version 生成文档的 OpenAPI 版本
+const Version = "3.1.0"
+
+// Document OpenAPI文档, 只包含生成时用到的部分
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+}
+
+// Info 文档的基本信息
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Components 可以被引用的结构体
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Operation 一个接口
+type Operation struct {
+	OperationID string               `json:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter 路径或query参数
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody 请求体
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response 响应
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType 某种Content-Type的内容
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+const (
+	mimeJSON = "application/json"
+	// 错误响应的结构体名, 同 resp.ErrorBody
+	errorSchemaName = "Error"
+)
+
+// Generate 根据路由生成文档, 只包含由 router.Handle 创建的路由
+func Generate(info Info, routes []router.Route) *Document {
+	g := newGenerator()
+	// 先占用名字, 业务中的同名结构体加上包名
+	g.schemas[errorSchemaName] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"code":       {Type: "integer"},
+			"message":    {Type: "string"},
+			"details":    {},
+			"request_id": {Type: "string"},
+		},
+		Required: []string{"code", "message"},
+	}
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]map[string]*Operation{},
+	}
+	for _, rt := range routes {
+		if rt.Request == nil && rt.Response == nil {
+			continue
+		}
+		path := convertPath(rt.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]*Operation{}
+		}
+		doc.Paths[path][strings.ToLower(rt.Method)] = g.operation(rt)
+	}
+	doc.Components.Schemas = g.schemas
+	return doc
+}
+
+// JSON 格式化后的json
+func (d *Document) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// YAML 与 JSON 的内容一致
+func (d *Document) YAML() ([]byte, error) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	// 经由 yaml.Node 保持json中的字段顺序
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	clearStyle(&node)
+	return yaml.Marshal(&node)
+}
+
+// operation 一条路由对应的接口
+func (g *generator) operation(rt router.Route) *Operation {
+	op := &Operation{
+		OperationID: operationID(rt.Method, rt.Path),
+		Summary:     rt.Summary,
+		Responses:   map[string]*Response{},
+	}
+	if rt.Request != nil {
+		var body *Schema
+		op.Parameters, body = g.request(rt.Request, hasBody(rt.Method))
+		if body != nil {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]*MediaType{mimeJSON: {Schema: body}},
+			}
+		}
+		if len(op.Parameters) > 0 || body != nil {
+			op.Responses["400"] = errorResponse("Bad Request")
+		}
+	}
+	data := &Schema{}
+	if rt.Response != nil {
+		data = g.schema(rt.Response)
+	}
+	// 同 resp.Body
+	op.Responses["200"] = &Response{
+		Description: "OK",
+		Content: map[string]*MediaType{mimeJSON: {Schema: &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"code":    {Type: "integer"},
+				"message": {Type: "string"},
+				"data":    data,
+			},
+			Required: []string{"code", "message", "data"},
+		}}},
+	}
+	op.Responses["default"] = errorResponse("Error")
+	return op
+}
+
+// request 请求结构体中 uri、form tag 的字段作为参数, 其他字段作为body
+func (g *generator) request(t reflect.Type, withBody bool) ([]*Parameter, *Schema) {
+	t = indirect(t)
+	if t.Kind() != reflect.Struct {
+		if withBody {
+			return nil, g.schema(t)
+		}
+		return nil, nil
+	}
+	var params []*Parameter
+	body := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	allBody := true
+	for _, f := range structFields(t) {
+		if name := tagName(f.Tag.Get("uri")); name != "" {
+			params = append(params, &Parameter{Name: name, In: "path", Required: true, Schema: g.fieldSchema(f)})
+			allBody = false
+			continue
+		}
+		if name := tagName(f.Tag.Get("form")); name != "" {
+			s := g.fieldSchema(f)
+			params = append(params, &Parameter{Name: name, In: "query", Required: isRequired(f), Schema: s})
+			allBody = false
+			continue
+		}
+		name := jsonName(f)
+		if name == "" {
+			continue
+		}
+		body.Properties[name] = g.fieldSchema(f)
+		if isRequired(f) {
+			body.Required = append(body.Required, name)
+		}
+	}
+	if !withBody || len(body.Properties) == 0 {
+		return params, nil
+	}
+	// 所有字段都在body中时引用结构体
+	if allBody && t.Name() != "" {
+		return params, g.schema(t)
+	}
+	sort.Strings(body.Required)
+	return params, body
+}
+
+func errorResponse(description string) *Response {
+	return &Response{
+		Description: description,
+		Content:     map[string]*MediaType{mimeJSON: {Schema: ref(errorSchemaName)}},
+	}
+}
+
+// convertPath gin的 /user/:id、/files/*path 转为 /user/{id}、/files/{path}
+func convertPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// operationID 如 PUT /api/user/:id 为 putApiUserById
+func operationID(method string, path string) string {
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(method))
+	for _, s := range strings.Split(path, "/") {
+		if s == "" {
+			continue
+		}
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			sb.WriteString("By")
+			s = s[1:]
+		}
+		for _, word := range strings.FieldsFunc(s, func(r rune) bool { return r == '-' || r == '_' || r == '.' }) {
+			sb.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return sb.String()
+}
+
+func hasBody(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		return true
+	}
+	return false
+}
+
+// clearStyle 去掉json带来的引号及flow风格, 使用yaml默认的风格
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		clearStyle(n)
+	}
+}
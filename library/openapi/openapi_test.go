@@ -0,0 +1,125 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 20:47:19
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 20:47:19
+ * @Description: 文档生成测试
+ */
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liziwei01/simple-boot/library/router"
+	"gopkg.in/yaml.v3"
+)
+
+type listUsersReq struct {
+	Page   int    `form:"page,default=1" binding:"min=1"`
+	Status string `form:"status" binding:"omitempty,oneof=active banned"`
+}
+
+type user struct {
+	ID      int64     `json:"id"`
+	Email   string    `json:"email" binding:"required,email"`
+	Name    string    `json:"name" binding:"required,min=2,max=32"`
+	Tags    []string  `json:"tags,omitempty" binding:"max=5,dive,max=8"`
+	Friends []*user   `json:"friends,omitempty"`
+	Created time.Time `json:"created"`
+	secret  string
+}
+
+type updateUserReq struct {
+	ID   int64  `uri:"id" binding:"required"`
+	Name string `json:"name" binding:"required"`
+}
+
+func TestGenerate(t *testing.T) {
+	r := router.New(gin.New())
+	api := r.Group("/api")
+	list := router.GET("/users", func(ctx context.Context, req *listUsersReq) (*[]user, error) { return nil, nil })
+	list.Summary = "list users"
+	api.Register(
+		list,
+		router.POST("/users", func(ctx context.Context, req *user) (*user, error) { return req, nil }),
+		router.PUT("/users/:id", func(ctx context.Context, req *updateUserReq) (*struct{}, error) { return nil, nil }),
+	)
+
+	doc := Generate(Info{Title: "demo", Version: "1.0.0"}, r.Routes())
+	data, err := doc.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 按json重新解析, 与序列化后的结果比较
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	lookup := func(path ...string) interface{} {
+		var v interface{} = got
+		for _, p := range path {
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				t.Fatalf("%v: not an object at %q", path, p)
+			}
+			v = m[p]
+		}
+		return v
+	}
+	checks := []struct {
+		path []string
+		want string
+	}{
+		{[]string{"openapi"}, `"3.1.0"`},
+		{[]string{"paths", "/api/users", "get", "summary"}, `"list users"`},
+		{[]string{"paths", "/api/users", "get", "operationId"}, `"getApiUsers"`},
+		{[]string{"paths", "/api/users", "get", "parameters"}, `[{"in":"query","name":"page","schema":{"default":1,"minimum":1,"type":"integer"}},{"in":"query","name":"status","schema":{"enum":["active","banned"],"type":"string"}}]`},
+		{[]string{"paths", "/api/users", "get", "responses", "200", "content", "application/json", "schema", "properties", "data"}, `{"items":{"$ref":"#/components/schemas/user"},"type":"array"}`},
+		{[]string{"paths", "/api/users", "post", "requestBody", "content", "application/json", "schema"}, `{"$ref":"#/components/schemas/user"}`},
+		{[]string{"paths", "/api/users/{id}", "put", "parameters"}, `[{"in":"path","name":"id","required":true,"schema":{"format":"int64","type":"integer"}}]`},
+		{[]string{"paths", "/api/users/{id}", "put", "requestBody", "content", "application/json", "schema"}, `{"properties":{"name":{"type":"string"}},"required":["name"],"type":"object"}`},
+		{[]string{"components", "schemas", "user", "required"}, `["email","name"]`},
+		{[]string{"components", "schemas", "user", "properties", "email"}, `{"format":"email","type":"string"}`},
+		{[]string{"components", "schemas", "user", "properties", "name"}, `{"maxLength":32,"minLength":2,"type":"string"}`},
+		{[]string{"components", "schemas", "user", "properties", "tags"}, `{"items":{"type":"string"},"maxItems":5,"type":"array"}`},
+		{[]string{"components", "schemas", "user", "properties", "friends"}, `{"items":{"$ref":"#/components/schemas/user"},"type":"array"}`},
+		{[]string{"components", "schemas", "user", "properties", "created"}, `{"format":"date-time","type":"string"}`},
+		{[]string{"components", "schemas", "user", "properties", "secret"}, `null`},
+	}
+	for _, c := range checks {
+		b, _ := json.Marshal(lookup(c.path...))
+		if string(b) != c.want {
+			t.Errorf("%s:\n got %s\nwant %s", strings.Join(c.path, "."), b, c.want)
+		}
+	}
+
+	// yaml与json内容一致
+	y, err := doc.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromYAML map[string]interface{}
+	if err := yaml.Unmarshal(y, &fromYAML); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(y), "openapi: 3.1.0\n") || lookupYAML(fromYAML, "paths", "/api/users/{id}", "put", "responses", "400") == nil {
+		t.Fatalf("yaml:\n%s", y)
+	}
+}
+
+func lookupYAML(m map[string]interface{}, path ...string) interface{} {
+	var v interface{} = m
+	for _, p := range path {
+		mm, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = mm[p]
+	}
+	return v
+}
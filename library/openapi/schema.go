@@ -0,0 +1,331 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 20:47:19
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 20:47:19
+ * @Description: 结构体转换为 JSON Schema
+ */
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema JSON Schema, 只包含生成时用到的部分
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	// json.RawMessage 等任意内容
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	// 结构体名中不能出现在 $ref 中的字符, 如泛型的 []
+	invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+)
+
+// generator 记录生成过的结构体, 相同的结构体只生成一次
+type generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newGenerator() *generator {
+	return &generator{
+		schemas: map[string]*Schema{},
+		names:   map[reflect.Type]string{},
+	}
+}
+
+// schema 有名字的结构体放在 components 中, 返回引用
+func (g *generator) schema(t reflect.Type) *Schema {
+	t = indirect(t)
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &Schema{}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer"}
+	case reflect.Int32, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name, has := g.names[t]
+		if !has {
+			name = g.name(t)
+			g.names[t] = name
+			// 先占位, 避免递归的结构体无限展开
+			g.schemas[name] = &Schema{}
+			*g.schemas[name] = *g.structSchema(t)
+		}
+		return ref(name)
+	}
+	// interface{} 等任意类型
+	return &Schema{}
+}
+
+// structSchema 导出的字段按json tag生成属性
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for _, f := range structFields(t) {
+		name := jsonName(f)
+		if name == "" {
+			continue
+		}
+		s.Properties[name] = g.fieldSchema(f)
+		if isRequired(f) {
+			s.Required = append(s.Required, name)
+		}
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+// fieldSchema 字段的类型及 binding、form tag 中的约束
+func (g *generator) fieldSchema(f reflect.StructField) *Schema {
+	s := g.schema(f.Type)
+	if s.Ref != "" {
+		return s
+	}
+	applyRules(s, f.Tag.Get("binding"))
+	for _, opt := range strings.Split(f.Tag.Get("form"), ",")[1:] {
+		if v, ok := strings.CutPrefix(opt, "default="); ok {
+			s.Default = parseValue(s.Type, v)
+		}
+	}
+	return s
+}
+
+// name 结构体在 components 中的名字, 重名时加上包名
+func (g *generator) name(t reflect.Type) string {
+	name := invalidNameChars.ReplaceAllString(t.Name(), "_")
+	if _, has := g.schemas[name]; !has {
+		return name
+	}
+	pkg := t.PkgPath()
+	name = invalidNameChars.ReplaceAllString(pkg[strings.LastIndex(pkg, "/")+1:], "_") + "." + name
+	for i, n := 2, name; ; i++ {
+		if _, has := g.schemas[n]; !has {
+			return n
+		}
+		n = name + strconv.Itoa(i)
+	}
+}
+
+// applyRules binding tag 中的校验规则转为约束, dive之后的规则作用于元素, 不再处理
+func applyRules(s *Schema, rules string) {
+	for _, rule := range strings.Split(rules, ",") {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "dive":
+			return
+		case "min", "gte":
+			setMin(s, value, false)
+		case "gt":
+			setMin(s, value, true)
+		case "max", "lte":
+			setMax(s, value, false)
+		case "lt":
+			setMax(s, value, true)
+		case "len":
+			setMin(s, value, false)
+			setMax(s, value, false)
+		case "oneof":
+			for _, v := range strings.Fields(value) {
+				s.Enum = append(s.Enum, parseValue(s.Type, v))
+			}
+		case "email":
+			s.Format = "email"
+		case "url", "uri":
+			s.Format = "uri"
+		case "uuid", "uuid4":
+			s.Format = "uuid"
+		case "ipv4", "ipv6", "hostname":
+			s.Format = key
+		case "datetime":
+			s.Format = "date-time"
+		}
+	}
+}
+
+// setMin 数字为最小值, 字符串为最小长度, 数组为最少元素数
+func setMin(s *Schema, value string, exclusive bool) {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return
+	}
+	switch s.Type {
+	case "integer", "number":
+		if exclusive {
+			s.ExclusiveMinimum = &n
+		} else {
+			s.Minimum = &n
+		}
+	case "string":
+		l := int(n)
+		if exclusive {
+			l++
+		}
+		s.MinLength = &l
+	case "array":
+		l := int(n)
+		if exclusive {
+			l++
+		}
+		s.MinItems = &l
+	}
+}
+
+// setMax 同 setMin
+func setMax(s *Schema, value string, exclusive bool) {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return
+	}
+	switch s.Type {
+	case "integer", "number":
+		if exclusive {
+			s.ExclusiveMaximum = &n
+		} else {
+			s.Maximum = &n
+		}
+	case "string":
+		l := int(n)
+		if exclusive {
+			l--
+		}
+		s.MaxLength = &l
+	case "array":
+		l := int(n)
+		if exclusive {
+			l--
+		}
+		s.MaxItems = &l
+	}
+}
+
+// parseValue 按类型解析tag中的值, 用于 default、enum
+func parseValue(typ string, v string) interface{} {
+	switch typ {
+	case "integer":
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return v
+}
+
+// structFields 导出的字段, 没有json tag的匿名结构体展开
+func structFields(t reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Tag.Get("json") == "" && indirect(f.Type).Kind() == reflect.Struct {
+			fields = append(fields, structFields(indirect(f.Type))...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// jsonName 字段在json中的名字, json:"-" 时为空
+func jsonName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name := tagName(tag); name != "" {
+		return name
+	}
+	return f.Name
+}
+
+// tagName tag中逗号前的名字, - 为空
+func tagName(tag string) string {
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// isRequired binding tag 中有 required
+func isRequired(f reflect.StructField) bool {
+	for _, rule := range strings.Split(f.Tag.Get("binding"), ",") {
+		if rule == "dive" {
+			return false
+		}
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+func ref(name string) *Schema {
+	return &Schema{Ref: fmt.Sprintf("#/components/schemas/%s", name)}
+}
@@ -29,6 +29,11 @@ type Client interface {
 	connect(ctx context.Context, bucket string) (*oss.Bucket, error)
 }
 
+// pinger 可以检查连通性的客户端实现, SetClient 注入的客户端(如测试中的假客户端)可以不实现, 此时健康检查不请求OSS
+type pinger interface {
+	ping(ctx context.Context) error
+}
+
 type client struct {
 	conf *Config
 }
@@ -51,3 +56,21 @@ func (c *client) connect(ctx context.Context, bucket string) (*oss.Bucket, error
 	}
 	return ossBucket, nil
 }
+
+// ping 检查endpoint及AccessKey是否可用, 用于健康检查
+// 使用bucket级别的 GetBucketInfo, 不需要账号级别的 ListBuckets 权限
+func (c *client) ping(ctx context.Context) error {
+	client, err := oss.New(c.conf.OSS.Endpoint, c.conf.OSS.AccessKeyID, c.conf.OSS.AccessKeySecret)
+	if err != nil {
+		return fmt.Errorf("oss.New: %w", err)
+	}
+	if c.conf.OSS.HealthBucket == "" {
+		return nil
+	}
+	_, err = client.GetBucketInfo(c.conf.OSS.HealthBucket, oss.WithContext(ctx))
+	return err
+}
+
+// 为了在编译期即确保实现了接口
+var _ Client = (*client)(nil)
+var _ pinger = (*client)(nil)
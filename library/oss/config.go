@@ -10,11 +10,14 @@ package oss
 // Config 配置
 type Config struct {
 	// Service的名字, 必选
-	Name string
+	Name string `validate:"required"`
 
 	OSS struct {
-		Endpoint        string
+		Endpoint        string `validate:"required"`
 		AccessKeyID     string
 		AccessKeySecret string
+		// HealthBucket 健康检查时读取该bucket的信息, 只需要bucket级别的 oss:GetBucketInfo 权限
+		// 为空时只检查配置, 不请求OSS
+		HealthBucket string
 	}
 }
@@ -13,6 +13,7 @@ import (
 	"io"
 
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/liziwei01/simple-boot/library/trace"
 )
 
 func (c *client) Get(ctx context.Context, bucket string, objectKey string) (*bytes.Reader, error) {
@@ -21,10 +22,11 @@ func (c *client) Get(ctx context.Context, bucket string, objectKey string) (*byt
 		return nil, err
 	}
 	file, err := ossBucket.GetObject(objectKey)
-	defer file.Close()
+	c.log(ctx, "GetObject", bucket, objectKey, err)
 	if err != nil {
 		return nil, err
 	}
+	defer file.Close()
 	fileBytes, err := io.ReadAll(file)
 	if err != nil {
 		return nil, err
@@ -38,6 +40,7 @@ func (c *client) Put(ctx context.Context, bucket string, objectKey string, fileR
 		return err
 	}
 	err = ossBucket.PutObject(objectKey, fileReader)
+	c.log(ctx, "PutObject", bucket, objectKey, err)
 	if err != nil {
 		return err
 	}
@@ -50,6 +53,7 @@ func (c *client) Del(ctx context.Context, bucket string, objectKey string) error
 		return err
 	}
 	err = ossBucket.DeleteObject(objectKey)
+	c.log(ctx, "DeleteObject", bucket, objectKey, err)
 	if err != nil {
 		return err
 	}
@@ -67,3 +71,11 @@ func (c *client) GetURL(ctx context.Context, bucket string, objectKey string) (s
 	}
 	return url, nil
 }
+
+// log 请求失败时打印日志, 带上ctx中的请求id
+func (c *client) log(ctx context.Context, op string, bucket string, objectKey string, err error) {
+	if err == nil {
+		return
+	}
+	trace.Logf(ctx, "[OSS] service=%s op=%s bucket=%s object=%q error=%v", c.conf.Name, op, bucket, objectKey, err)
+}
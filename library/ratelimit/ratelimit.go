@@ -0,0 +1,151 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 19:06:27
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 19:06:27
+ * @Description: 进程内的令牌桶限流
+ */
+package ratelimit
+
+import (
+	"hash/maphash"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// 分片数, 降低锁竞争
+	shardCount = 64
+	// DefaultTTL 桶多久没有访问后被清理
+	DefaultTTL = 10 * time.Minute
+)
+
+// Limiter 按key限流
+type Limiter interface {
+	// Allow 是否允许本次请求, 不允许时返回需要等待的时间
+	Allow(key string) (ok bool, retryAfter time.Duration)
+	// Check 同 Allow, 但允许时不消耗额度, 用于同时满足多个限制时先检查再消耗
+	Check(key string) (ok bool, retryAfter time.Duration)
+}
+
+// TokenBucket 每个key一个令牌桶, 桶存放在分片的map中, 长时间没有访问的桶会被清理
+type TokenBucket struct {
+	// 每秒产生的令牌数
+	rate float64
+	// 桶的容量
+	burst float64
+	ttl   time.Duration
+	seed  maphash.Seed
+
+	shards [shardCount]shard
+
+	// 用于测试
+	now func() time.Time
+}
+
+type shard struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	// 上次清理过期桶的时间
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket rate为每秒产生的令牌数, burst为桶的容量, 至少为1
+// ttl为桶多久没有访问后被清理, 为0时使用 DefaultTTL
+func NewTokenBucket(rate float64, burst int, ttl time.Duration) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	tb := &TokenBucket{
+		rate:  rate,
+		burst: float64(burst),
+		ttl:   ttl,
+		seed:  maphash.MakeSeed(),
+		now:   time.Now,
+	}
+	for i := range tb.shards {
+		tb.shards[i].buckets = make(map[string]*bucket)
+	}
+	return tb
+}
+
+// Allow 消耗一个令牌, 没有令牌时返回下一个令牌产生需要的时间
+func (tb *TokenBucket) Allow(key string) (bool, time.Duration) {
+	return tb.take(key, true)
+}
+
+// Check 是否有令牌, 不消耗
+func (tb *TokenBucket) Check(key string) (bool, time.Duration) {
+	return tb.take(key, false)
+}
+
+// take 补充令牌后判断是否有令牌, consume为true时消耗一个
+func (tb *TokenBucket) take(key string, consume bool) (bool, time.Duration) {
+	now := tb.now()
+	s := &tb.shards[maphash.String(tb.seed, key)%shardCount]
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now, tb.ttl)
+
+	b, has := s.buckets[key]
+	if !has {
+		if !consume {
+			// 新的桶是满的, 不为只检查的请求创建
+			return true, 0
+		}
+		b = &bucket{tokens: tb.burst, last: now}
+		s.buckets[key] = b
+	}
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(tb.burst, b.tokens+elapsed.Seconds()*tb.rate)
+	}
+	b.last = now
+	if b.tokens >= 1 {
+		if consume {
+			b.tokens--
+		}
+		return true, 0
+	}
+	if tb.rate <= 0 {
+		return false, tb.ttl
+	}
+	wait := time.Duration((1 - b.tokens) / tb.rate * float64(time.Second))
+	return false, wait
+}
+
+// Len 当前的桶数
+func (tb *TokenBucket) Len() int {
+	n := 0
+	for i := range tb.shards {
+		s := &tb.shards[i]
+		s.mu.Lock()
+		n += len(s.buckets)
+		s.mu.Unlock()
+	}
+	return n
+}
+
+// sweep 每隔ttl清理一次长时间没有访问的桶, 访问时顺便执行, 不需要后台goroutine
+func (s *shard) sweep(now time.Time, ttl time.Duration) {
+	if now.Sub(s.lastSweep) < ttl {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if now.Sub(b.last) >= ttl {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// 为了在编译期即确保实现了接口
+var _ Limiter = (*TokenBucket)(nil)
@@ -0,0 +1,54 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 19:06:27
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 19:06:27
+ * @Description: 令牌桶测试
+ */
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Unix(1000, 0)
+	tb := NewTokenBucket(2, 3, time.Minute)
+	tb.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := tb.Allow("a"); !ok {
+			t.Fatalf("request %d should be allowed", i)
+		}
+	}
+	// 只检查不消耗
+	if ok, retryAfter := tb.Check("a"); ok || retryAfter != 500*time.Millisecond {
+		t.Fatalf("check: ok=%v retryAfter=%s", ok, retryAfter)
+	}
+	if ok, _ := tb.Check("c"); !ok || tb.Len() != 1 {
+		t.Fatalf("check should not create a bucket, len=%d", tb.Len())
+	}
+	ok, retryAfter := tb.Allow("a")
+	if ok || retryAfter != 500*time.Millisecond {
+		t.Fatalf("ok=%v retryAfter=%s", ok, retryAfter)
+	}
+	// 其他key不受影响
+	if ok, _ := tb.Allow("b"); !ok {
+		t.Fatal("key b should be allowed")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := tb.Allow("a"); !ok {
+		t.Fatal("token should be refilled")
+	}
+
+	// 超过ttl没有访问的桶在分片被访问时清理
+	now = now.Add(2 * time.Minute)
+	for i := range tb.shards {
+		tb.shards[i].sweep(now, tb.ttl)
+	}
+	if n := tb.Len(); n != 0 {
+		t.Fatalf("len=%d, expired buckets not swept", n)
+	}
+}
@@ -9,30 +9,34 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
 	"sync"
 
 	"github.com/liziwei01/simple-boot/library/conf"
 	"github.com/liziwei01/simple-boot/library/env"
+	"github.com/liziwei01/simple-boot/library/health"
+	"github.com/liziwei01/simple-boot/library/lifecycle"
 )
 
 const (
 	// mysql conf file path
 	mysqlPath = "/servicer/"
-	suffix    = ".toml"
 )
 
 var (
-	// conf file root path
-	configPath = env.Default.ConfDir()
 	// mysql client map, client use single instance mode
 	clients map[string]Client
-	// 初始化互斥锁
-	initMux sync.Mutex
+	// 读写clients的锁, closeClients 等会在其他goroutine中修改
+	initMux sync.RWMutex
 )
 
+func init() {
+	// 应用退出时关闭所有连接
+	_ = lifecycle.OnStop("redis", closeClients)
+}
+
 /**
  * @description:
  * @param {context.Context} ctx
@@ -41,10 +45,11 @@ var (
  */
 func GetClient(ctx context.Context, serviceName string) (Client, error) {
 	// try to get from single instance map
-	if client, hasSet := clients[serviceName]; hasSet {
-		if client != nil {
-			return client, nil
-		}
+	initMux.RLock()
+	client, hasSet := clients[serviceName]
+	initMux.RUnlock()
+	if hasSet && client != nil {
+		return client, nil
 	}
 	// set a new instance
 	client, err := setClient(serviceName)
@@ -63,6 +68,10 @@ func setClient(serviceName string) (Client, error) {
 	// 互斥锁
 	initMux.Lock()
 	defer initMux.Unlock()
+	// 等锁期间可能已经被其他goroutine初始化
+	if client, hasSet := clients[serviceName]; hasSet && client != nil {
+		return client, nil
+	}
 	// 初始化
 	client, err := initClient(serviceName)
 	if err == nil {
@@ -71,11 +80,40 @@ func setClient(serviceName string) (Client, error) {
 		}
 		// 添加
 		clients[serviceName] = client
+		registerHealth(serviceName)
 		return client, nil
 	}
 	return nil, err
 }
 
+/**
+ * @description: replace the client of serviceName, e.g. with a fake in tests
+ * @param {string} serviceName
+ * @param {Client} client
+ * @return {func()} restore the previous client
+ */
+func SetClient(serviceName string, client Client) (restore func()) {
+	initMux.Lock()
+	defer initMux.Unlock()
+	if clients == nil {
+		clients = make(map[string]Client)
+	}
+	prev, had := clients[serviceName]
+	clients[serviceName] = client
+	return func() {
+		initMux.Lock()
+		defer initMux.Unlock()
+		if clients == nil {
+			clients = make(map[string]Client)
+		}
+		if had {
+			clients[serviceName] = prev
+		} else {
+			delete(clients, serviceName)
+		}
+	}
+}
+
 /**
  * @description: according to conf service, read conf from conf file to init mysql client
  * @param {string} serviceName
@@ -83,14 +121,66 @@ func setClient(serviceName string) (Client, error) {
  */
 func initClient(serviceName string) (Client, error) {
 	var config *Config
-	fileAbs, err := filepath.Abs(filepath.Join(configPath, mysqlPath, serviceName+suffix))
+	// 支持 .toml、.yaml、.yml、.json
+	fileAbs, err := conf.FindFile(filepath.Join(configPath(), mysqlPath), serviceName)
 	if err != nil {
 		return nil, err
 	}
-	if _, err := os.Stat(fileAbs); !os.IsNotExist(err) {
-		conf.Default.Parse(fileAbs, &config)
-		client := New(config)
-		return client, nil
+	if err := conf.Default.Parse(fileAbs, &config); err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("conf file not exist")
+	client := New(config)
+	return client, nil
+}
+
+/**
+ * @description: close all redis clients, registered as lifecycle stop hook
+ * @param {context.Context} ctx
+ * @return {*}
+ */
+func closeClients(ctx context.Context) error {
+	initMux.Lock()
+	defer initMux.Unlock()
+	var errs []error
+	for serviceName, client := range clients {
+		p, ok := client.(pool)
+		if !ok {
+			continue
+		}
+		if err := p.close(); err != nil {
+			errs = append(errs, fmt.Errorf("close %q: %w", serviceName, err))
+		}
+	}
+	clients = nil
+	return errors.Join(errs...)
+}
+
+/**
+ * @description: register a readiness check for the service, the client is looked up on every check without creating one
+ * @param {string} serviceName
+ * @return {*}
+ */
+func registerHealth(serviceName string) {
+	// 重复注册会失败, 检查时总是取最新的client, 忽略即可
+	_ = health.RegisterFunc("redis."+serviceName, func(ctx context.Context) error {
+		// 不能使用GetClient, 退出关闭后的检查会重新创建client
+		initMux.RLock()
+		client, has := clients[serviceName]
+		initMux.RUnlock()
+		if !has {
+			return fmt.Errorf("redis client %q is closed", serviceName)
+		}
+		if p, ok := client.(pool); ok {
+			return p.ping(ctx)
+		}
+		return nil
+	})
+}
+
+/**
+ * @description: conf file root path, read on every use so that the conf dir chosen by bootstrap.Setup takes effect
+ * @return {*}
+ */
+func configPath() string {
+	return env.Default.ConfDir()
 }
@@ -44,6 +44,13 @@ type Client interface {
 	dbname() int
 }
 
+// pool 持有连接池的客户端实现
+// SetClient 注入的客户端(如测试中的假客户端)可以不实现, 此时退出时不关闭, 健康检查时不检查连接
+type pool interface {
+	close() error
+	ping(ctx context.Context) error
+}
+
 type client struct {
 	conf *Config
 	db   *r.Client
@@ -67,6 +74,26 @@ func (c *client) connect(ctx context.Context) (*r.Client, error) {
 	return c.db, err
 }
 
+func (c *client) close() error {
+	mu.Lock()
+	defer mu.Unlock()
+	if c.db == nil {
+		return nil
+	}
+	err := c.db.Close()
+	c.db = nil
+	return err
+}
+
+// ping 检查连接是否可用, 用于健康检查
+func (c *client) ping(ctx context.Context) error {
+	db, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	return db.WithContext(ctx).Ping().Err()
+}
+
 func (c *client) open() (*r.Client, error) {
 	var (
 		db  *r.Client
@@ -117,3 +144,7 @@ func (c *client) readTimeOut() int {
 func (c *client) retry() int {
 	return c.conf.Retry
 }
+
+// 为了在编译期即确保实现了接口
+var _ Client = (*client)(nil)
+var _ pool = (*client)(nil)
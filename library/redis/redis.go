@@ -9,7 +9,11 @@ package redis
 
 import (
 	"context"
+	"strings"
 	"time"
+
+	r "github.com/go-redis/redis"
+	"github.com/liziwei01/simple-boot/library/trace"
 )
 
 func (c *client) Get(ctx context.Context, key string) (value string, err error) {
@@ -18,6 +22,7 @@ func (c *client) Get(ctx context.Context, key string) (value string, err error)
 		return "", err
 	}
 	ret, err := db.Get(key).Result()
+	c.log(ctx, "GET", key, err)
 	if err != nil {
 		return "", err
 	}
@@ -34,6 +39,7 @@ func (c *client) Set(ctx context.Context, key string, value string, expireTime .
 		exp = expireTime[0]
 	}
 	err = db.Set(key, value, exp).Err()
+	c.log(ctx, "SET", key, err)
 	if err != nil {
 		return err
 	}
@@ -46,6 +52,7 @@ func (c *client) Del(ctx context.Context, keys ...string) error {
 		return err
 	}
 	err = db.Del(keys...).Err()
+	c.log(ctx, "DEL", strings.Join(keys, " "), err)
 	if err != nil {
 		return err
 	}
@@ -58,8 +65,17 @@ func (c *client) Exists(ctx context.Context, keys ...string) (int64, error) {
 		return 0, err
 	}
 	ret, err := db.Exists(keys...).Result()
+	c.log(ctx, "EXISTS", strings.Join(keys, " "), err)
 	if err != nil {
 		return 0, err
 	}
 	return ret, nil
 }
+
+// log 命令失败时打印日志, 带上ctx中的请求id, key不存在不视为失败
+func (c *client) log(ctx context.Context, cmd string, keys string, err error) {
+	if err == nil || err == r.Nil {
+		return
+	}
+	trace.Logf(ctx, "[Redis] service=%s cmd=%s keys=%q error=%v", c.name(), cmd, keys, err)
+}
@@ -0,0 +1,101 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 21:15:08
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 21:15:08
+ * @Description: 统一的json响应
+ */
+
+// Package resp 成功、失败时统一的json响应
+//
+//	成功 {"code":0,"message":"ok","data":...}
+//	失败 {"code":10001,"message":"user not found","details":...,"request_id":"..."}
+package resp
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liziwei01/simple-boot/library/errs"
+	"github.com/liziwei01/simple-boot/library/trace"
+)
+
+// Body 成功时的响应
+type Body struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data"`
+}
+
+// ErrorBody 失败时的响应
+type ErrorBody struct {
+	Code      int         `json:"code"`
+	Message   string      `json:"message"`
+	Details   interface{} `json:"details,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+// OK 200, data放在data字段中
+func OK(c *gin.Context, data interface{}) {
+	c.JSON(http.StatusOK, Body{Code: 0, Message: "ok", Data: data})
+}
+
+// Error 按 errs.From(err) 的状态码返回, 并中止后续的handler
+// 信息按 Accept-Language 本地化; 错误带有原因或者被包装过时, 带上请求id打印整个错误链
+func Error(c *gin.Context, err error) {
+	e := errs.From(err)
+	// 原因及外层的上下文只在日志中
+	if errors.Unwrap(e) != nil || err != error(e) {
+		trace.Logf(c, "[resp] %s %s status=%d code=%d error: %v", c.Request.Method, c.Request.URL.Path, e.StatusCode(), e.Code, err)
+	}
+	_ = c.Error(err)
+	c.AbortWithStatusJSON(e.StatusCode(), ErrorBody{
+		Code:      e.Code,
+		Message:   errs.Localize(e, Languages(c)...),
+		Details:   e.Details,
+		RequestID: trace.RequestID(c),
+	})
+}
+
+// Languages Accept-Language中的语言, 按q值从大到小, q值相同时按出现的顺序, 不包括q=0及*
+// 如 en;q=0.8,zh-CN,zh;q=0.9 为 [zh-CN zh en]
+func Languages(c *gin.Context) []string {
+	header := c.GetHeader("Accept-Language")
+	if header == "" {
+		return nil
+	}
+	type weighted struct {
+		lang string
+		q    float64
+	}
+	var list []weighted
+	for _, part := range strings.Split(header, ",") {
+		lang, params, _ := strings.Cut(part, ";")
+		if lang = strings.TrimSpace(lang); lang == "" || lang == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				// 格式不对的视为0, 不使用
+				q, _ = strconv.ParseFloat(strings.TrimSpace(v), 64)
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		list = append(list, weighted{lang: lang, q: q})
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].q > list[j].q
+	})
+	langs := make([]string, 0, len(list))
+	for _, w := range list {
+		langs = append(langs, w.lang)
+	}
+	return langs
+}
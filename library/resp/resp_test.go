@@ -0,0 +1,80 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 21:15:08
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 21:15:08
+ * @Description: 响应测试
+ */
+package resp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liziwei01/simple-boot/library/errs"
+	"github.com/liziwei01/simple-boot/library/trace"
+)
+
+func TestResp(t *testing.T) {
+	var logs bytes.Buffer
+	logger := trace.Logger
+	trace.Logger = log.New(&logs, "", 0)
+	t.Cleanup(func() { trace.Logger = logger })
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.ContextWithFallback = true
+	engine.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(trace.NewContext(c.Request.Context(), trace.Trace{RequestID: "req-1"}))
+	})
+	engine.GET("/ok", func(c *gin.Context) { OK(c, gin.H{"a": 1}) })
+	engine.GET("/forbidden", func(c *gin.Context) {
+		Error(c, errs.ErrForbidden.WithDetails([]string{"admin"}))
+	})
+	engine.GET("/wrapped", func(c *gin.Context) {
+		Error(c, fmt.Errorf("load profile: %w", errs.ErrNotFound.Wrap(errors.New("no rows"))))
+	})
+	engine.GET("/plain", func(c *gin.Context) { Error(c, errors.New("db down")) })
+
+	cases := []struct {
+		target string
+		code   int
+		body   string
+		log    string
+	}{
+		{"/ok", 200, `{"code":0,"message":"ok","data":{"a":1}}`, ""},
+		{"/forbidden", 403, `{"code":403,"message":"forbidden","details":["admin"],"request_id":"req-1"}`, ""},
+		{"/wrapped", 404, `{"code":404,"message":"not found","request_id":"req-1"}`, "[request_id=req-1 trace_id=] [resp] GET /wrapped status=404 code=404 error: load profile: [404] not found: no rows"},
+		{"/plain", 500, `{"code":500,"message":"internal server error","request_id":"req-1"}`, "error: db down"},
+	}
+	for _, c := range cases {
+		logs.Reset()
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, c.target, nil))
+		if w.Code != c.code || w.Body.String() != c.body {
+			t.Errorf("%s: %d %s", c.target, w.Code, w.Body)
+		}
+		if (c.log == "") != (logs.Len() == 0) || !strings.Contains(logs.String(), c.log) {
+			t.Errorf("%s: log %q", c.target, logs.String())
+		}
+	}
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.Header.Set("Accept-Language", "zh-CN,zh;q=0.9, en;q=0.8,*")
+	if got := strings.Join(Languages(c), " "); got != "zh-CN zh en" {
+		t.Fatalf("Languages=%q", got)
+	}
+	// 按q值排序, q相同时保持顺序, 去掉q=0
+	c.Request.Header.Set("Accept-Language", "fr;q=0, en;q=0.5, ja;q=0.8, zh, ko;q=0.8")
+	if got := strings.Join(Languages(c), " "); got != "zh ja ko en" {
+		t.Fatalf("Languages=%q", got)
+	}
+}
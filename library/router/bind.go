@@ -0,0 +1,100 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 20:21:45
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 20:21:45
+ * @Description: 请求绑定及校验
+ */
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/liziwei01/simple-boot/library/errs"
+)
+
+// Bind 将请求绑定到obj并校验, 失败时返回 errs.ErrBadRequest, body超过限制时返回 errs.ErrRequestEntityTooLarge
+//
+//	type GetUserReq struct {
+//		ID   int64  `uri:"id" binding:"required"`
+//		Page int    `form:"page,default=1" binding:"min=1"`
+//		Name string `json:"name" binding:"max=32"`
+//	}
+//
+// 依次绑定query(form tag)、body(json, 或表单时form tag)、路径参数(uri tag), 后绑定的覆盖先绑定的
+// 所有来源绑定完成后按binding tag校验一次
+func Bind(c *gin.Context, obj interface{}) error {
+	isStruct := reflect.TypeOf(obj).Elem().Kind() == reflect.Struct
+	if isStruct {
+		if err := binding.MapFormWithTag(obj, c.Request.URL.Query(), "form"); err != nil {
+			return badRequest(err)
+		}
+	}
+	if err := bindBody(c.Request, obj, isStruct); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return errs.ErrRequestEntityTooLarge.Wrap(err)
+		}
+		return badRequest(err)
+	}
+	if isStruct && len(c.Params) > 0 {
+		params := make(map[string][]string, len(c.Params))
+		for _, p := range c.Params {
+			params[p.Key] = []string{p.Value}
+		}
+		if err := binding.MapFormWithTag(obj, params, "uri"); err != nil {
+			return badRequest(err)
+		}
+	}
+	if binding.Validator != nil {
+		if err := binding.Validator.ValidateStruct(obj); err != nil {
+			return badRequest(err)
+		}
+	}
+	return nil
+}
+
+// badRequest 绑定或校验的错误信息返回给客户端, 属于客户端的问题, 不作为原因打印日志
+func badRequest(err error) error {
+	return errs.ErrBadRequest.WithMessage("%s", err.Error())
+}
+
+// bindBody 按Content-Type绑定body, 没有body时跳过
+func bindBody(req *http.Request, obj interface{}, isStruct bool) error {
+	if req.Body == nil || req.Body == http.NoBody || req.ContentLength == 0 {
+		return nil
+	}
+	contentType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	switch contentType {
+	case gin.MIMEPOSTForm, gin.MIMEMultipartPOSTForm:
+		if !isStruct {
+			return nil
+		}
+		if err := req.ParseMultipartForm(defaultMultipartMemory); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+			return err
+		}
+		return binding.MapFormWithTag(obj, req.PostForm, "form")
+	default:
+		dec := json.NewDecoder(req.Body)
+		if binding.EnableDecoderUseNumber {
+			dec.UseNumber()
+		}
+		if binding.EnableDecoderDisallowUnknownFields {
+			dec.DisallowUnknownFields()
+		}
+		if err := dec.Decode(obj); err != nil && err != io.EOF {
+			return err
+		}
+		return nil
+	}
+}
+
+// defaultMultipartMemory 同gin, 表单中的文件超过后写入临时文件
+const defaultMultipartMemory = 32 << 20
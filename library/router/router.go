@@ -0,0 +1,195 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 20:21:45
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 20:21:45
+ * @Description: 声明式的路由表
+ */
+
+// Package router 控制器声明路由, handler为 func(ctx, *Req) (*Resp, error)
+// 请求从路径、query、body绑定到Req并校验, 响应及错误使用 resp 统一的json格式
+//
+//	type UserController struct{}
+//
+//	func (uc *UserController) Routes() []router.Route {
+//		return []router.Route{
+//			router.GET("/user/:id", uc.Get),
+//			router.POST("/user", uc.Create, auth),
+//		}
+//	}
+//
+//	router.New(engine).Group("/api").Mount(&UserController{})
+package router
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liziwei01/simple-boot/library/resp"
+)
+
+// Route 一条路由
+type Route struct {
+	Method string
+	// Path 相对于所在分组的路径, 注册后为完整路径
+	Path string
+	// Summary 简要说明, 用于生成文档
+	Summary string
+	// Middlewares 仅对该路由生效的中间件, 在Handler之前执行
+	Middlewares []gin.HandlerFunc
+	Handler     gin.HandlerFunc
+	// Request、Response 请求及响应的类型, 由 Handle 设置, 用于生成文档
+	Request  reflect.Type
+	Response reflect.Type
+}
+
+// Controller 一组路由的声明
+type Controller interface {
+	Routes() []Route
+}
+
+// Router 在gin上注册路由, 并记录所有注册过的路由
+type Router interface {
+	// Register 注册路由, 重复的路由由gin panic
+	Register(routes ...Route)
+	// Mount 注册控制器声明的所有路由
+	Mount(controllers ...Controller)
+	// Group 创建一个路径前缀及中间件的分组, 与当前Router共用路由表
+	Group(prefix string, middlewares ...gin.HandlerFunc) Router
+	// Routes 所有注册过的路由, 按注册顺序, Path为完整路径
+	Routes() []Route
+}
+
+// New 在engine(或其分组)上创建Router
+func New(engine gin.IRouter) Router {
+	base := "/"
+	if bp, ok := engine.(interface{ BasePath() string }); ok {
+		base = bp.BasePath()
+	}
+	return &router{
+		engine: engine,
+		base:   base,
+		table:  &table{},
+	}
+}
+
+type router struct {
+	engine gin.IRouter
+	base   string
+	table  *table
+}
+
+// table 路由表, 分组之间共享
+type table struct {
+	mu     sync.RWMutex
+	routes []Route
+}
+
+func (r *router) Register(routes ...Route) {
+	for _, rt := range routes {
+		method := strings.ToUpper(rt.Method)
+		handlers := append(append([]gin.HandlerFunc{}, rt.Middlewares...), rt.Handler)
+		r.engine.Handle(method, rt.Path, handlers...)
+
+		rt.Method = method
+		rt.Path = joinPaths(r.base, rt.Path)
+		r.table.mu.Lock()
+		r.table.routes = append(r.table.routes, rt)
+		r.table.mu.Unlock()
+	}
+}
+
+func (r *router) Mount(controllers ...Controller) {
+	for _, c := range controllers {
+		r.Register(c.Routes()...)
+	}
+}
+
+func (r *router) Group(prefix string, middlewares ...gin.HandlerFunc) Router {
+	return &router{
+		engine: r.engine.Group(prefix, middlewares...),
+		base:   joinPaths(r.base, prefix),
+		table:  r.table,
+	}
+}
+
+func (r *router) Routes() []Route {
+	r.table.mu.RLock()
+	defer r.table.mu.RUnlock()
+	return append([]Route(nil), r.table.routes...)
+}
+
+// GET 创建一条GET路由, 见 Handle
+func GET[Req any, Resp any](path string, fn func(ctx context.Context, req *Req) (*Resp, error), middlewares ...gin.HandlerFunc) Route {
+	return Handle(http.MethodGet, path, fn, middlewares...)
+}
+
+// POST 创建一条POST路由, 见 Handle
+func POST[Req any, Resp any](path string, fn func(ctx context.Context, req *Req) (*Resp, error), middlewares ...gin.HandlerFunc) Route {
+	return Handle(http.MethodPost, path, fn, middlewares...)
+}
+
+// PUT 创建一条PUT路由, 见 Handle
+func PUT[Req any, Resp any](path string, fn func(ctx context.Context, req *Req) (*Resp, error), middlewares ...gin.HandlerFunc) Route {
+	return Handle(http.MethodPut, path, fn, middlewares...)
+}
+
+// PATCH 创建一条PATCH路由, 见 Handle
+func PATCH[Req any, Resp any](path string, fn func(ctx context.Context, req *Req) (*Resp, error), middlewares ...gin.HandlerFunc) Route {
+	return Handle(http.MethodPatch, path, fn, middlewares...)
+}
+
+// DELETE 创建一条DELETE路由, 见 Handle
+func DELETE[Req any, Resp any](path string, fn func(ctx context.Context, req *Req) (*Resp, error), middlewares ...gin.HandlerFunc) Route {
+	return Handle(http.MethodDelete, path, fn, middlewares...)
+}
+
+// Handle 创建一条路由, 请求绑定到Req并校验后调用fn, 见 Bind
+// fn返回的Resp及错误分别由 resp.OK、resp.Error 返回
+// fn中已经写过响应(如下载文件)时不再写入, ctx为 *gin.Context, 见 GinContext
+func Handle[Req any, Resp any](method string, path string, fn func(ctx context.Context, req *Req) (*Resp, error), middlewares ...gin.HandlerFunc) Route {
+	return Route{
+		Method:      method,
+		Path:        path,
+		Middlewares: middlewares,
+		Handler: func(c *gin.Context) {
+			req := new(Req)
+			if err := Bind(c, req); err != nil {
+				resp.Error(c, err)
+				return
+			}
+			data, err := fn(c, req)
+			if err != nil {
+				resp.Error(c, err)
+				return
+			}
+			if c.Writer.Written() {
+				return
+			}
+			resp.OK(c, data)
+		},
+		Request:  reflect.TypeOf((*Req)(nil)).Elem(),
+		Response: reflect.TypeOf((*Resp)(nil)).Elem(),
+	}
+}
+
+// GinContext handler中的ctx对应的 *gin.Context, 不是由 Handle 调用时返回nil
+func GinContext(ctx context.Context) *gin.Context {
+	c, _ := ctx.(*gin.Context)
+	return c
+}
+
+// joinPaths 拼接分组前缀与路径, 保留路径末尾的/
+func joinPaths(base string, path string) string {
+	if path == "" {
+		return base
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// 为了在编译期即确保实现了接口
+var _ Router = (*router)(nil)
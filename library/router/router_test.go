@@ -0,0 +1,119 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 20:21:45
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 20:21:45
+ * @Description: 路由测试
+ */
+package router
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/liziwei01/simple-boot/library/errs"
+)
+
+type updateUserReq struct {
+	ID     int64  `uri:"id" binding:"required"`
+	Notify bool   `form:"notify"`
+	Page   int    `form:"page,default=1" binding:"min=1"`
+	Name   string `json:"name" binding:"required,max=8"`
+}
+
+type updateUserResp struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Notify bool   `json:"notify"`
+	Page   int    `json:"page"`
+}
+
+type userController struct{}
+
+func (uc *userController) Routes() []Route {
+	return []Route{
+		PUT("/user/:id", uc.Update),
+		GET("/user/:id", uc.Get, func(c *gin.Context) { c.Header("X-Mw", "1") }),
+	}
+}
+
+func (uc *userController) Update(ctx context.Context, req *updateUserReq) (*updateUserResp, error) {
+	return &updateUserResp{ID: req.ID, Name: req.Name, Notify: req.Notify, Page: req.Page}, nil
+}
+
+func (uc *userController) Get(ctx context.Context, req *struct {
+	ID int64 `uri:"id"`
+}) (*updateUserResp, error) {
+	switch req.ID {
+	case 404:
+		return nil, errs.ErrNotFound.WithMessage("user not found")
+	case 409:
+		// 业务错误码及详情同样由 resp.Error 返回
+		return nil, (&errs.Error{Code: 10409, Status: http.StatusConflict, Message: "user exists"}).WithDetails(map[string]int64{"id": req.ID})
+	case 500:
+		return nil, errors.New("db down")
+	}
+	if GinContext(ctx) == nil {
+		return nil, errors.New("no gin context")
+	}
+	return nil, nil
+}
+
+func TestRouter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	r := New(engine)
+	r.Group("/api").Mount(&userController{})
+
+	routes := r.Routes()
+	if len(routes) != 2 || routes[0].Method != http.MethodPut || routes[0].Path != "/api/user/:id" {
+		t.Fatalf("routes=%+v", routes)
+	}
+	if routes[0].Request.Name() != "updateUserReq" || routes[0].Response.Name() != "updateUserResp" {
+		t.Fatalf("types: %v %v", routes[0].Request, routes[0].Response)
+	}
+
+	cases := []struct {
+		method, target, body string
+		code                 int
+		want                 string
+	}{
+		// 路径参数覆盖body中的同名字段, query的默认值
+		{http.MethodPut, "/api/user/7?notify=true", `{"name":"bob","ID":1}`, 200, `{"code":0,"message":"ok","data":{"id":7,"name":"bob","notify":true,"page":1}}`},
+		{http.MethodPut, "/api/user/7?page=0", `{"name":"bob"}`, 400, `"code":400`},
+		{http.MethodPut, "/api/user/7", `{"name":"too long name"}`, 400, `'max'`},
+		{http.MethodPut, "/api/user/7", `{"name":`, 400, `unexpected EOF`},
+		{http.MethodGet, "/api/user/1", ``, 200, `{"code":0,"message":"ok","data":null}`},
+		{http.MethodGet, "/api/user/404", ``, 404, `{"code":404,"message":"user not found"}`},
+		{http.MethodGet, "/api/user/409", ``, 409, `{"code":10409,"message":"user exists","details":{"id":409}}`},
+		{http.MethodGet, "/api/user/500", ``, 500, `{"code":500,"message":"internal server error"}`},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+		if tc.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		engine.ServeHTTP(w, req)
+		if w.Code != tc.code || !strings.Contains(w.Body.String(), tc.want) {
+			t.Errorf("%s %s: %d %s", tc.method, tc.target, w.Code, w.Body)
+		}
+		if tc.method == http.MethodGet && w.Header().Get("X-Mw") != "1" {
+			t.Errorf("%s %s: route middleware not run", tc.method, tc.target)
+		}
+	}
+
+	// 表单body
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/user/3", strings.NewReader("Name=amy&page=2"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	engine.ServeHTTP(w, req)
+	if w.Code != 200 || !strings.Contains(w.Body.String(), `"name":"amy","notify":false,"page":2`) {
+		t.Errorf("form: %d %s", w.Code, w.Body)
+	}
+}
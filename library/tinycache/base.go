@@ -9,8 +9,6 @@ package tinycache
 
 import (
 	"context"
-	"fmt"
-	"os"
 	"path/filepath"
 	"sync"
 
@@ -21,16 +19,13 @@ import (
 const (
 	// tinycache conf file path
 	tinycachePath = "/servicer/"
-	suffix        = ".toml"
 )
 
 var (
-	// conf file root path
-	configPath = env.Default.ConfDir()
 	// tinycache client map, client use single instance mode
 	clients map[string]Client
-	// 初始化互斥锁
-	initMux sync.Mutex
+	// 读写clients的锁, closeClients 等会在其他goroutine中修改
+	initMux sync.RWMutex
 )
 
 /**
@@ -41,10 +36,11 @@ var (
  */
 func GetClient(ctx context.Context, serviceName string) (Client, error) {
 	// try to get from single instance map
-	if client, hasSet := clients[serviceName]; hasSet {
-		if client != nil {
-			return client, nil
-		}
+	initMux.RLock()
+	client, hasSet := clients[serviceName]
+	initMux.RUnlock()
+	if hasSet && client != nil {
+		return client, nil
 	}
 	// set a new instance
 	client, err := setClient(serviceName)
@@ -63,6 +59,10 @@ func setClient(serviceName string) (Client, error) {
 	// 互斥锁
 	initMux.Lock()
 	defer initMux.Unlock()
+	// 等锁期间可能已经被其他goroutine初始化
+	if client, hasSet := clients[serviceName]; hasSet && client != nil {
+		return client, nil
+	}
 	// 初始化
 	client, err := initClient(serviceName)
 	if err == nil {
@@ -83,14 +83,22 @@ func setClient(serviceName string) (Client, error) {
  */
 func initClient(serviceName string) (Client, error) {
 	var config *Config
-	fileAbs, err := filepath.Abs(filepath.Join(configPath, tinycachePath, serviceName+suffix))
+	// 支持 .toml、.yaml、.yml、.json
+	fileAbs, err := conf.FindFile(filepath.Join(configPath(), tinycachePath), serviceName)
 	if err != nil {
 		return nil, err
 	}
-	if _, err := os.Stat(fileAbs); !os.IsNotExist(err) {
-		conf.Default.Parse(fileAbs, &config)
-		client := New(config)
-		return client, nil
+	if err := conf.Default.Parse(fileAbs, &config); err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("conf file not exist")
+	client := New(config)
+	return client, nil
+}
+
+/**
+ * @description: conf file root path, read on every use so that the conf dir chosen by bootstrap.Setup takes effect
+ * @return {*}
+ */
+func configPath() string {
+	return env.Default.ConfDir()
 }
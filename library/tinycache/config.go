@@ -10,7 +10,7 @@ package tinycache
 // Config 配置
 type Config struct {
 	// Service的名字, 必选
-	Name string
+	Name string `validate:"required"`
 
 	// 各种自定义的参数, 全部非必选
 	// 超时
@@ -0,0 +1,26 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 17:05:31
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 17:05:31
+ * @Description: 带请求id的日志
+ */
+package trace
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// Logger 日志输出, 默认为标准库的log
+var Logger = log.Default()
+
+// Logf 输出一行日志, ctx中有追踪信息时带上 request_id、trace_id
+func Logf(ctx context.Context, format string, v ...interface{}) {
+	msg := fmt.Sprintf(format, v...)
+	if t, ok := FromContext(ctx); ok {
+		msg = fmt.Sprintf("[request_id=%s trace_id=%s] %s", t.RequestID, t.TraceID, msg)
+	}
+	_ = Logger.Output(2, msg)
+}
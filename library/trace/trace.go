@@ -0,0 +1,164 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 17:05:31
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 17:05:31
+ * @Description: 请求id及W3C trace context, 通过context.Context在各组件间传递
+ */
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+const (
+	// RequestIDHeader 请求id的header
+	RequestIDHeader = "X-Request-Id"
+	// TraceParentHeader W3C trace context的header, 格式为 00-<trace-id>-<parent-id>-<flags>
+	TraceParentHeader = "traceparent"
+
+	traceParentVersion = "00"
+	flagSampled        = "01"
+	flagNotSampled     = "00"
+	// 上游传入的请求id的最大长度, 超过时不使用
+	maxRequestIDLen = 128
+)
+
+// Trace 一个请求的追踪信息
+type Trace struct {
+	// RequestID 请求id, 上游没有传入时与TraceID相同
+	RequestID string
+	// TraceID 32位16进制, 整个调用链相同
+	TraceID string
+	// SpanID 16位16进制, 当前服务处理请求的id
+	SpanID string
+	// ParentID 上游的SpanID, 没有上游时为空
+	ParentID string
+	// Sampled 是否被采样
+	Sampled bool
+}
+
+type ctxKey struct{}
+
+// New 生成一个新的调用链
+func New() Trace {
+	traceID := randomHex(16)
+	return Trace{
+		RequestID: traceID,
+		TraceID:   traceID,
+		SpanID:    randomHex(8),
+		Sampled:   true,
+	}
+}
+
+// FromHeader 从上游的 X-Request-Id、traceparent header 中继续调用链, 不合法的值会被忽略
+func FromHeader(h http.Header) Trace {
+	t := New()
+	if traceID, parentID, sampled, ok := ParseTraceParent(h.Get(TraceParentHeader)); ok {
+		t.TraceID = traceID
+		t.ParentID = parentID
+		t.Sampled = sampled
+		t.RequestID = traceID
+	}
+	if id := h.Get(RequestIDHeader); ValidRequestID(id) {
+		t.RequestID = id
+	}
+	return t
+}
+
+// TraceParent 当前服务作为上游时的traceparent
+func (t Trace) TraceParent() string {
+	flags := flagNotSampled
+	if t.Sampled {
+		flags = flagSampled
+	}
+	return traceParentVersion + "-" + t.TraceID + "-" + t.SpanID + "-" + flags
+}
+
+// Inject 将请求id及traceparent写入header, 用于响应或请求下游
+func (t Trace) Inject(h http.Header) {
+	if t.RequestID != "" {
+		h.Set(RequestIDHeader, t.RequestID)
+	}
+	if t.TraceID != "" && t.SpanID != "" {
+		h.Set(TraceParentHeader, t.TraceParent())
+	}
+}
+
+// NewContext 返回带有t的ctx
+func NewContext(ctx context.Context, t Trace) context.Context {
+	return context.WithValue(ctx, ctxKey{}, t)
+}
+
+// FromContext 获取ctx中的追踪信息
+func FromContext(ctx context.Context) (Trace, bool) {
+	if ctx == nil {
+		return Trace{}, false
+	}
+	t, ok := ctx.Value(ctxKey{}).(Trace)
+	return t, ok
+}
+
+// RequestID 获取ctx中的请求id, 没有时返回空
+func RequestID(ctx context.Context) string {
+	t, _ := FromContext(ctx)
+	return t.RequestID
+}
+
+// ParseTraceParent 解析traceparent, 全0的id及ff版本视为不合法
+func ParseTraceParent(s string) (traceID string, parentID string, sampled bool, ok bool) {
+	// 高版本可能在后面追加字段
+	if len(s) < 55 || (len(s) > 55 && s[55] != '-') {
+		return "", "", false, false
+	}
+	version, traceID, parentID, flags := s[0:2], s[3:35], s[36:52], s[53:55]
+	if s[2] != '-' || s[35] != '-' || s[52] != '-' {
+		return "", "", false, false
+	}
+	if !isLowerHex(version) || version == "ff" || (version == traceParentVersion && len(s) != 55) {
+		return "", "", false, false
+	}
+	if !isLowerHex(traceID) || !isLowerHex(parentID) || !isLowerHex(flags) ||
+		isZero(traceID) || isZero(parentID) {
+		return "", "", false, false
+	}
+	flagBits, _ := hex.DecodeString(flags)
+	return traceID, parentID, flagBits[0]&1 == 1, true
+}
+
+// ValidRequestID 只接受可打印的ASCII字符, 避免日志注入
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= '0' && c <= '9') && !(c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isZero(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
@@ -0,0 +1,61 @@
+/*
+ * @Author: liziwei01
+ * @Date: 2026-10-18 17:05:31
+ * @LastEditors: liziwei01
+ * @LastEditTime: 2026-10-18 17:05:31
+ * @Description: trace测试
+ */
+package trace
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	cases := []struct {
+		in      string
+		ok      bool
+		sampled bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true, true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", true, false},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", true, true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false, false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false, false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false, false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false, false},
+		{"", false, false},
+	}
+	for _, c := range cases {
+		_, _, sampled, ok := ParseTraceParent(c.in)
+		if ok != c.ok || sampled != c.sampled {
+			t.Errorf("ParseTraceParent(%q)=%v,%v want %v,%v", c.in, sampled, ok, c.sampled, c.ok)
+		}
+	}
+}
+
+func TestFromHeader(t *testing.T) {
+	h := http.Header{}
+	h.Set(TraceParentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	tr := FromHeader(h)
+	if tr.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || tr.ParentID != "00f067aa0ba902b7" || tr.RequestID != tr.TraceID {
+		t.Fatalf("trace=%+v", tr)
+	}
+	if tr.SpanID == tr.ParentID || len(tr.SpanID) != 16 {
+		t.Fatalf("span id=%q", tr.SpanID)
+	}
+
+	h.Set(RequestIDHeader, "upstream-id")
+	ctx := NewContext(context.Background(), FromHeader(h))
+	if got := RequestID(ctx); got != "upstream-id" {
+		t.Fatalf("request id=%q", got)
+	}
+
+	out := http.Header{}
+	tr.Inject(out)
+	if _, _, _, ok := ParseTraceParent(out.Get(TraceParentHeader)); !ok {
+		t.Fatalf("injected traceparent=%q", out.Get(TraceParentHeader))
+	}
+}